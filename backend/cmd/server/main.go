@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
 
+	"guildquest/internal/catalog"
 	"guildquest/internal/config"
 	"guildquest/internal/database"
+	"guildquest/internal/jobs"
 	"guildquest/internal/middleware"
 	"guildquest/internal/routes"
+	"guildquest/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -20,6 +24,14 @@ import (
 func main() {
 	cfg := config.Load()
 
+	// Shop catalog override
+	if cfg.DecorationCatalogPath != "" {
+		if err := catalog.LoadDecorationFile(cfg.DecorationCatalogPath); err != nil {
+			log.Fatalf("Decoration catalog failed: %v", err)
+		}
+		log.Printf("Loaded decoration catalog version %d", catalog.DecorationVersion())
+	}
+
 	// DB
 	db, err := database.Connect(cfg)
 	if err != nil {
@@ -29,6 +41,15 @@ func main() {
 		log.Fatalf("DB migration failed: %v", err)
 	}
 
+	// Attachment storage
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("Storage setup failed: %v", err)
+	}
+
+	// Background jobs
+	jobs.Start(context.Background(), db, store, cfg)
+
 	// Handlers
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -67,7 +88,7 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	routes.SetupRoutesWithAuth(api, db, cfg.JWTSecret)
+	routes.SetupRoutesWithAuth(api, db, store, cfg)
 	// Start
 	port := os.Getenv("PORT")
 	if port == "" {
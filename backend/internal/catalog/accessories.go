@@ -0,0 +1,114 @@
+// internal/catalog/accessories.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	slots          []string
+	accessories    map[string]Accessory
+	accessoryOrder []string
+)
+
+// Accessory is a decoration a pet can wear in one of the equipment slots
+type Accessory struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Slot        string  `json:"slot"`
+	Price       int     `json:"price"`
+	Bonuses     Bonuses `json:"bonuses"`
+	// Species limits who can wear it; empty means every species
+	Species []string `json:"species,omitempty"`
+}
+
+// Bonuses are percentage boosts an equipped accessory gives its pet
+type Bonuses struct {
+	ExpPercent       int `json:"expPercent,omitempty"`
+	HappinessPercent int `json:"happinessPercent,omitempty"`
+}
+
+// Fits reports whether a pet of the species can wear the accessory
+func (a Accessory) Fits(species string) bool {
+	if len(a.Species) == 0 {
+		return true
+	}
+	for _, sp := range a.Species {
+		if sp == species {
+			return true
+		}
+	}
+	return false
+}
+
+type accessoryFile struct {
+	Version     int         `json:"version"`
+	Slots       []string    `json:"slots"`
+	Accessories []Accessory `json:"accessories"`
+}
+
+func loadAccessories(data []byte) error {
+	var file accessoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("accessory catalog: %w", err)
+	}
+
+	known := make(map[string]bool, len(file.Slots))
+	for _, slot := range file.Slots {
+		known[slot] = true
+	}
+
+	byID := make(map[string]Accessory, len(file.Accessories))
+	ids := make([]string, 0, len(file.Accessories))
+	for _, acc := range file.Accessories {
+		if acc.ID == "" {
+			return fmt.Errorf("accessory catalog: accessory without id")
+		}
+		if _, dup := byID[acc.ID]; dup {
+			return fmt.Errorf("accessory catalog: duplicate accessory %q", acc.ID)
+		}
+		if !known[acc.Slot] {
+			return fmt.Errorf("accessory catalog: accessory %q has unknown slot %q", acc.ID, acc.Slot)
+		}
+		byID[acc.ID] = acc
+		ids = append(ids, acc.ID)
+	}
+
+	mu.Lock()
+	slots, accessories, accessoryOrder = file.Slots, byID, ids
+	mu.Unlock()
+	return nil
+}
+
+// FindAccessory looks up an accessory by ID
+func FindAccessory(id string) (Accessory, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	acc, ok := accessories[id]
+	return acc, ok
+}
+
+// AllAccessories returns every accessory in catalog order
+func AllAccessories() []Accessory {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Accessory, len(accessoryOrder))
+	for i, id := range accessoryOrder {
+		out[i] = accessories[id]
+	}
+	return out
+}
+
+// IsSlot reports whether slot is an equipment slot
+func IsSlot(slot string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, s := range slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// internal/catalog/achievements.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Achievement metrics: what an achievement's goal is measured in
+const (
+	MetricQuestsCompleted  = "quests_completed"
+	MetricQuestStreak      = "quest_streak"
+	MetricPetLevel         = "pet_level"
+	MetricDecorationsOwned = "decorations_owned"
+)
+
+var metrics = []string{MetricQuestsCompleted, MetricQuestStreak, MetricPetLevel, MetricDecorationsOwned}
+
+var (
+	achievements     map[string]Achievement
+	achievementOrder []string
+)
+
+// Achievement unlocks once the user's Metric reaches Goal
+type Achievement struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Metric      string            `json:"metric"`
+	Goal        int               `json:"goal"`
+	Reward      AchievementReward `json:"reward"`
+}
+
+// AchievementReward is paid out when an achievement unlocks
+type AchievementReward struct {
+	Gold int `json:"gold,omitempty"`
+	// Items maps item IDs to quantities; a toy the user already owns is
+	// paid out at its shop price instead
+	Items map[string]int `json:"items,omitempty"`
+}
+
+type achievementFile struct {
+	Version      int           `json:"version"`
+	Achievements []Achievement `json:"achievements"`
+}
+
+func loadAchievements(data []byte) error {
+	var file achievementFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("achievement catalog: %w", err)
+	}
+
+	byID := make(map[string]Achievement, len(file.Achievements))
+	ids := make([]string, 0, len(file.Achievements))
+	for _, a := range file.Achievements {
+		if a.ID == "" {
+			return fmt.Errorf("achievement catalog: achievement without id")
+		}
+		if _, dup := byID[a.ID]; dup {
+			return fmt.Errorf("achievement catalog: duplicate achievement %q", a.ID)
+		}
+		if !isMetric(a.Metric) {
+			return fmt.Errorf("achievement catalog: achievement %q has unknown metric %q", a.ID, a.Metric)
+		}
+		if a.Goal <= 0 {
+			return fmt.Errorf("achievement catalog: achievement %q needs a goal", a.ID)
+		}
+		for id := range a.Reward.Items {
+			if _, ok := FindItem(id); !ok {
+				return fmt.Errorf("achievement catalog: achievement %q rewards unknown item %q", a.ID, id)
+			}
+		}
+		byID[a.ID] = a
+		ids = append(ids, a.ID)
+	}
+
+	mu.Lock()
+	achievements, achievementOrder = byID, ids
+	mu.Unlock()
+	return nil
+}
+
+func isMetric(metric string) bool {
+	for _, m := range metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAchievement looks up an achievement by ID
+func FindAchievement(id string) (Achievement, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := achievements[id]
+	return a, ok
+}
+
+// AllAchievements returns every achievement in catalog order
+func AllAchievements() []Achievement {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Achievement, len(achievementOrder))
+	for i, id := range achievementOrder {
+		out[i] = achievements[id]
+	}
+	return out
+}
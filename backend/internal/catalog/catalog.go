@@ -0,0 +1,143 @@
+// internal/catalog/catalog.go
+package catalog
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+//go:embed data/*.json
+var defaults embed.FS
+
+var (
+	mu      sync.RWMutex
+	species map[string]Species
+	order   []string
+)
+
+func init() {
+	if err := loadSpecies(mustRead("data/species.json")); err != nil {
+		panic(err)
+	}
+	if err := loadEvolutions(mustRead("data/evolutions.json")); err != nil {
+		panic(err)
+	}
+	if err := loadItems(mustRead("data/items.json")); err != nil {
+		panic(err)
+	}
+	if err := loadAccessories(mustRead("data/accessories.json")); err != nil {
+		panic(err)
+	}
+	if err := loadDecorations(mustRead("data/decorations.json")); err != nil {
+		panic(err)
+	}
+	if err := loadAchievements(mustRead("data/achievements.json")); err != nil {
+		panic(err)
+	}
+	if err := loadLoot(mustRead("data/loot.json")); err != nil {
+		panic(err)
+	}
+	if err := loadRecipes(mustRead("data/recipes.json")); err != nil {
+		panic(err)
+	}
+}
+
+func mustRead(name string) []byte {
+	data, err := defaults.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Species describes a kind of pet and how it grows
+type Species struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Starter species are given to new players for free
+	Starter bool `json:"starter"`
+	// Multipliers on the base EXP curve and decay rates
+	ExpCurve       float64  `json:"expCurve"`
+	HungerDecay    float64  `json:"hungerDecay"`
+	HappinessDecay float64  `json:"happinessDecay"`
+	Adoption       Adoption `json:"adoption"`
+}
+
+// Adoption lists what it takes to adopt a species. GoldCost > 0 allows
+// buying it; the milestones (if any) allow earning it for free.
+type Adoption struct {
+	GoldCost          int `json:"goldCost"`
+	MinCompletedTasks int `json:"minCompletedTasks"`
+	MinPetLevel       int `json:"minPetLevel"`
+}
+
+type speciesFile struct {
+	Version int       `json:"version"`
+	Species []Species `json:"species"`
+}
+
+func loadSpecies(data []byte) error {
+	var file speciesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("species catalog: %w", err)
+	}
+
+	byID := make(map[string]Species, len(file.Species))
+	ids := make([]string, 0, len(file.Species))
+	for _, sp := range file.Species {
+		if sp.ID == "" {
+			return fmt.Errorf("species catalog: species without id")
+		}
+		if _, dup := byID[sp.ID]; dup {
+			return fmt.Errorf("species catalog: duplicate species %q", sp.ID)
+		}
+		byID[sp.ID] = sp
+		ids = append(ids, sp.ID)
+	}
+
+	mu.Lock()
+	species, order = byID, ids
+	mu.Unlock()
+	return nil
+}
+
+// FindSpecies looks up a species by ID
+func FindSpecies(id string) (Species, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	sp, ok := species[id]
+	return sp, ok
+}
+
+// AllSpecies returns every species in catalog order
+func AllSpecies() []Species {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Species, len(order))
+	for i, id := range order {
+		out[i] = species[id]
+	}
+	return out
+}
+
+// StarterSpecies returns the species new players start with
+func StarterSpecies() Species {
+	for _, sp := range AllSpecies() {
+		if sp.Starter {
+			return sp
+		}
+	}
+	return AllSpecies()[0]
+}
+
+// SpeciesOrDefault returns the species, falling back to neutral multipliers
+// for pets of a species that is no longer in the catalog
+func SpeciesOrDefault(id string) Species {
+	if sp, ok := FindSpecies(id); ok {
+		return sp
+	}
+	return Species{ID: id, Name: id, ExpCurve: 1, HungerDecay: 1, HappinessDecay: 1}
+}
@@ -0,0 +1,163 @@
+// internal/catalog/decorations.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	decorationVersion int
+	decorations       map[string]Decoration
+	decorationOrder   []string
+)
+
+// Decoration is an item sold in the shop for the user's lair
+type Decoration struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	Rarity      string `json:"rarity"`
+	Price       int    `json:"price"`
+	// Layer is where it sits in the lair; only decorations on the same
+	// layer collide. Width and Height are its footprint in grid cells.
+	Layer  string `json:"layer"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// MinLevel is the pet level (of any of the user's pets) needed to buy it
+	MinLevel int `json:"minLevel,omitempty"`
+	// Seasonal decorations are only sold within [AvailableFrom, AvailableUntil)
+	AvailableFrom  *time.Time `json:"availableFrom,omitempty"`
+	AvailableUntil *time.Time `json:"availableUntil,omitempty"`
+}
+
+// OnSale reports whether the decoration can be bought at t
+func (d Decoration) OnSale(t time.Time) bool {
+	if d.AvailableFrom != nil && t.Before(*d.AvailableFrom) {
+		return false
+	}
+	if d.AvailableUntil != nil && !t.Before(*d.AvailableUntil) {
+		return false
+	}
+	return true
+}
+
+// Footprint is the decoration's width and height once rotated by
+// rotation degrees
+func (d Decoration) Footprint(rotation int) (int, int) {
+	if rotation == 90 || rotation == 270 {
+		return d.Height, d.Width
+	}
+	return d.Width, d.Height
+}
+
+type decorationFile struct {
+	Version     int          `json:"version"`
+	Categories  []string     `json:"categories"`
+	Rarities    []string     `json:"rarities"`
+	Layers      []string     `json:"layers"`
+	Decorations []Decoration `json:"decorations"`
+}
+
+// LoadDecorationFile replaces the built-in decoration catalog with the one
+// in path, so prices and seasonal stock can change without a release. The
+// file must be at least as new as the catalog it replaces.
+func LoadDecorationFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("decoration catalog: %w", err)
+	}
+	return loadDecorations(data)
+}
+
+func loadDecorations(data []byte) error {
+	var file decorationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("decoration catalog: %w", err)
+	}
+
+	knownCategory := make(map[string]bool, len(file.Categories))
+	for _, c := range file.Categories {
+		knownCategory[c] = true
+	}
+	knownRarity := make(map[string]bool, len(file.Rarities))
+	for _, r := range file.Rarities {
+		knownRarity[r] = true
+	}
+	knownLayer := make(map[string]bool, len(file.Layers))
+	for _, l := range file.Layers {
+		knownLayer[l] = true
+	}
+
+	byID := make(map[string]Decoration, len(file.Decorations))
+	ids := make([]string, 0, len(file.Decorations))
+	for _, d := range file.Decorations {
+		if d.ID == "" {
+			return fmt.Errorf("decoration catalog: decoration without id")
+		}
+		if _, dup := byID[d.ID]; dup {
+			return fmt.Errorf("decoration catalog: duplicate decoration %q", d.ID)
+		}
+		if _, clash := FindAccessory(d.ID); clash {
+			return fmt.Errorf("decoration catalog: %q is already an accessory", d.ID)
+		}
+		if !knownCategory[d.Category] {
+			return fmt.Errorf("decoration catalog: decoration %q has unknown category %q", d.ID, d.Category)
+		}
+		if !knownRarity[d.Rarity] {
+			return fmt.Errorf("decoration catalog: decoration %q has unknown rarity %q", d.ID, d.Rarity)
+		}
+		if !knownLayer[d.Layer] {
+			return fmt.Errorf("decoration catalog: decoration %q has unknown layer %q", d.ID, d.Layer)
+		}
+		if d.Width <= 0 || d.Height <= 0 {
+			return fmt.Errorf("decoration catalog: decoration %q needs a footprint", d.ID)
+		}
+		if d.Price <= 0 {
+			return fmt.Errorf("decoration catalog: decoration %q needs a price", d.ID)
+		}
+		if d.AvailableFrom != nil && d.AvailableUntil != nil && !d.AvailableFrom.Before(*d.AvailableUntil) {
+			return fmt.Errorf("decoration catalog: decoration %q is never available", d.ID)
+		}
+		byID[d.ID] = d
+		ids = append(ids, d.ID)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if file.Version < decorationVersion {
+		return fmt.Errorf("decoration catalog: version %d is older than the loaded version %d", file.Version, decorationVersion)
+	}
+	decorationVersion = file.Version
+	decorations, decorationOrder = byID, ids
+	return nil
+}
+
+// DecorationVersion is the version of the loaded decoration catalog
+func DecorationVersion() int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return decorationVersion
+}
+
+// FindDecoration looks up a decoration by ID
+func FindDecoration(id string) (Decoration, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	d, ok := decorations[id]
+	return d, ok
+}
+
+// AllDecorations returns every decoration in catalog order
+func AllDecorations() []Decoration {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Decoration, len(decorationOrder))
+	for i, id := range decorationOrder {
+		out[i] = decorations[id]
+	}
+	return out
+}
@@ -0,0 +1,209 @@
+// internal/catalog/evolutions.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	categories []Category
+	evolutions map[evolutionKey]Evolution
+)
+
+// Character attributes, grown by completing quests
+const (
+	AttrStrength     = "strength"
+	AttrIntelligence = "intelligence"
+	AttrConstitution = "constitution"
+	AttrPerception   = "perception"
+)
+
+var attributes = []string{AttrStrength, AttrIntelligence, AttrConstitution, AttrPerception}
+
+// Category is a kind of quest; the mix of categories a pet's owner
+// completes decides which way the pet evolves
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Attributes are the character attribute points a completed quest of
+	// the category earns
+	Attributes map[string]int `json:"attributes,omitempty"`
+}
+
+// Evolution is one step of a species' evolution tree: a pet of the species
+// in form From (empty for the base form) evolves on reaching Level.
+type Evolution struct {
+	Species  string            `json:"species"`
+	From     string            `json:"from"`
+	Level    int               `json:"level"`
+	Branches []EvolutionBranch `json:"branches"`
+}
+
+// EvolutionBranch is a form a pet can evolve into. A branch with a
+// Category is taken when that category is the owner's most completed one
+// and makes up at least MinShare of the pet's categorized quests; the
+// Default branch is taken otherwise.
+type EvolutionBranch struct {
+	Form     string  `json:"form"`
+	Name     string  `json:"name"`
+	Category string  `json:"category,omitempty"`
+	MinShare float64 `json:"minShare,omitempty"`
+	Default  bool    `json:"default,omitempty"`
+}
+
+type evolutionKey struct {
+	species string
+	from    string
+}
+
+type evolutionFile struct {
+	Version    int         `json:"version"`
+	Categories []Category  `json:"categories"`
+	Evolutions []Evolution `json:"evolutions"`
+}
+
+func loadEvolutions(data []byte) error {
+	var file evolutionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("evolution catalog: %w", err)
+	}
+
+	known := make(map[string]bool, len(file.Categories))
+	for _, c := range file.Categories {
+		known[c.ID] = true
+		for attr, points := range c.Attributes {
+			if !isAttribute(attr) || points <= 0 {
+				return fmt.Errorf("evolution catalog: category %q grows unknown attribute %q", c.ID, attr)
+			}
+		}
+	}
+
+	byKey := make(map[evolutionKey]Evolution, len(file.Evolutions))
+	for _, ev := range file.Evolutions {
+		if _, ok := FindSpecies(ev.Species); !ok {
+			return fmt.Errorf("evolution catalog: unknown species %q", ev.Species)
+		}
+		key := evolutionKey{ev.Species, ev.From}
+		if _, dup := byKey[key]; dup {
+			return fmt.Errorf("evolution catalog: duplicate evolution from %q/%q", ev.Species, ev.From)
+		}
+		if len(ev.Branches) == 0 {
+			return fmt.Errorf("evolution catalog: evolution from %q/%q has no branches", ev.Species, ev.From)
+		}
+		for _, b := range ev.Branches {
+			if b.Form == "" {
+				return fmt.Errorf("evolution catalog: branch without form in %q", ev.Species)
+			}
+			if b.Category != "" && !known[b.Category] {
+				return fmt.Errorf("evolution catalog: unknown category %q", b.Category)
+			}
+		}
+		byKey[key] = ev
+	}
+
+	mu.Lock()
+	categories, evolutions = file.Categories, byKey
+	mu.Unlock()
+	return nil
+}
+
+func isAttribute(attr string) bool {
+	for _, a := range attributes {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// FindCategory looks up a quest category by ID
+func FindCategory(id string) (Category, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, c := range categories {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// Categories returns the quest categories in catalog order
+func Categories() []Category {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Category(nil), categories...)
+}
+
+// IsCategory reports whether id is a known quest category
+func IsCategory(id string) bool {
+	for _, c := range Categories() {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NextEvolution returns the evolution available to a pet of the species in
+// the given form, if any
+func NextEvolution(species, form string) (Evolution, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	ev, ok := evolutions[evolutionKey{species, form}]
+	return ev, ok
+}
+
+// FormName returns the display name of a form, or the species name for the
+// base form
+func FormName(species, form string) string {
+	if form == "" {
+		return SpeciesOrDefault(species).Name
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for key, ev := range evolutions {
+		if key.species != species {
+			continue
+		}
+		for _, b := range ev.Branches {
+			if b.Form == form {
+				return b.Name
+			}
+		}
+	}
+	return form
+}
+
+// Choose picks the branch for a pet whose owner has completed the given
+// number of quests per category. It returns false when no branch applies.
+func (ev Evolution) Choose(tallies map[string]int) (EvolutionBranch, bool) {
+	total, top, topCount, tie := 0, "", 0, false
+	for category, count := range tallies {
+		total += count
+		switch {
+		case count > topCount:
+			top, topCount, tie = category, count, false
+		case count == topCount:
+			tie = true
+		}
+	}
+
+	if total > 0 && !tie {
+		share := float64(topCount) / float64(total)
+		for _, b := range ev.Branches {
+			if b.Category == top && share >= b.MinShare {
+				return b, true
+			}
+		}
+	}
+
+	for _, b := range ev.Branches {
+		if b.Default {
+			return b, true
+		}
+	}
+	return EvolutionBranch{}, false
+}
@@ -0,0 +1,128 @@
+// internal/catalog/items.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Item kinds
+const (
+	ItemFood   = "food"
+	ItemToy    = "toy"
+	ItemPotion = "potion"
+)
+
+var (
+	items     map[string]Item
+	itemOrder []string
+)
+
+// Item is something sold in the shop and used on a pet. Toys are kept
+// after use; food and potions are consumed.
+type Item struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Kind        string      `json:"kind"`
+	Price       int         `json:"price"`
+	Cooldown    Duration    `json:"cooldown"`
+	Effects     ItemEffects `json:"effects"`
+}
+
+// ItemEffects is what using an item does to a pet
+type ItemEffects struct {
+	Hunger    int `json:"hunger,omitempty"`
+	Happiness int `json:"happiness,omitempty"`
+	Exp       int `json:"exp,omitempty"`
+	// Cure makes a sick pet healthy; Revive brings a fainted pet back
+	Cure   bool `json:"cure,omitempty"`
+	Revive bool `json:"revive,omitempty"`
+}
+
+// Consumable reports whether using the item uses it up
+func (i Item) Consumable() bool {
+	return i.Kind != ItemToy
+}
+
+// Duration is a time.Duration written as a Go duration string ("30m")
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d == 0 {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+type itemFile struct {
+	Version int    `json:"version"`
+	Items   []Item `json:"items"`
+}
+
+func loadItems(data []byte) error {
+	var file itemFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("item catalog: %w", err)
+	}
+
+	byID := make(map[string]Item, len(file.Items))
+	ids := make([]string, 0, len(file.Items))
+	for _, it := range file.Items {
+		if it.ID == "" {
+			return fmt.Errorf("item catalog: item without id")
+		}
+		if _, dup := byID[it.ID]; dup {
+			return fmt.Errorf("item catalog: duplicate item %q", it.ID)
+		}
+		switch it.Kind {
+		case ItemFood, ItemToy, ItemPotion:
+		default:
+			return fmt.Errorf("item catalog: item %q has unknown kind %q", it.ID, it.Kind)
+		}
+		byID[it.ID] = it
+		ids = append(ids, it.ID)
+	}
+
+	mu.Lock()
+	items, itemOrder = byID, ids
+	mu.Unlock()
+	return nil
+}
+
+// FindItem looks up an item by ID
+func FindItem(id string) (Item, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	it, ok := items[id]
+	return it, ok
+}
+
+// AllItems returns every item in catalog order
+func AllItems() []Item {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Item, len(itemOrder))
+	for i, id := range itemOrder {
+		out[i] = items[id]
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+// internal/catalog/loot.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var lootTiers []LootTier
+
+// LootTier is the loot table for quests worth at least MinReward gold
+type LootTier struct {
+	ID        string `json:"id"`
+	MinReward int    `json:"minReward"`
+	// Chance is the probability that a quest drops anything at all
+	Chance float64 `json:"chance"`
+	// The PityAfter-th quest in a row without a rare drop is guaranteed
+	// one (0 disables the pity timer)
+	PityAfter int        `json:"pityAfter"`
+	Drops     []LootDrop `json:"drops"`
+}
+
+// LootDrop is one weighted entry of a loot table: either Quantity of a
+// consumable Item or Gold
+type LootDrop struct {
+	Item     string `json:"item,omitempty"`
+	Quantity int    `json:"quantity,omitempty"`
+	Gold     int    `json:"gold,omitempty"`
+	Weight   int    `json:"weight"`
+	Rare     bool   `json:"rare,omitempty"`
+}
+
+type lootFile struct {
+	Version int        `json:"version"`
+	Tiers   []LootTier `json:"tiers"`
+}
+
+func loadLoot(data []byte) error {
+	var file lootFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("loot catalog: %w", err)
+	}
+
+	if len(file.Tiers) == 0 || file.Tiers[0].MinReward != 0 {
+		return fmt.Errorf("loot catalog: the first tier must start at reward 0")
+	}
+	for i := range file.Tiers {
+		tier := &file.Tiers[i]
+		if tier.ID == "" {
+			return fmt.Errorf("loot catalog: tier without id")
+		}
+		if i > 0 && tier.MinReward <= file.Tiers[i-1].MinReward {
+			return fmt.Errorf("loot catalog: tier %q must start above the tier before it", tier.ID)
+		}
+		if tier.Chance <= 0 || tier.Chance > 1 {
+			return fmt.Errorf("loot catalog: tier %q needs a chance in (0, 1]", tier.ID)
+		}
+
+		hasRare := false
+		for j := range tier.Drops {
+			drop := &tier.Drops[j]
+			if (drop.Item == "") == (drop.Gold <= 0) {
+				return fmt.Errorf("loot catalog: tier %q drop %d needs either an item or gold", tier.ID, j)
+			}
+			if drop.Item != "" {
+				item, ok := FindItem(drop.Item)
+				if !ok || !item.Consumable() {
+					return fmt.Errorf("loot catalog: tier %q drops unknown consumable %q", tier.ID, drop.Item)
+				}
+				if drop.Quantity == 0 {
+					drop.Quantity = 1
+				}
+			}
+			if drop.Weight <= 0 {
+				return fmt.Errorf("loot catalog: tier %q drop %d needs a weight", tier.ID, j)
+			}
+			hasRare = hasRare || drop.Rare
+		}
+		if tier.PityAfter > 0 && !hasRare {
+			return fmt.Errorf("loot catalog: tier %q has a pity timer but no rare drops", tier.ID)
+		}
+	}
+
+	mu.Lock()
+	lootTiers = file.Tiers
+	mu.Unlock()
+	return nil
+}
+
+// LootTierFor returns the loot table for a quest worth reward gold
+func LootTierFor(reward int) LootTier {
+	mu.RLock()
+	defer mu.RUnlock()
+	tier := lootTiers[0]
+	for _, t := range lootTiers[1:] {
+		if reward >= t.MinReward {
+			tier = t
+		}
+	}
+	return tier
+}
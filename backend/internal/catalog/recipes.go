@@ -0,0 +1,120 @@
+// internal/catalog/recipes.go
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	recipes     map[string]Recipe
+	recipeOrder []string
+)
+
+// Recipe crafts its Output from inventory items and a gold fee
+type Recipe struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Items maps the item IDs used up to their quantities
+	Items  map[string]int `json:"items"`
+	Gold   int            `json:"gold"`
+	Output RecipeOutput   `json:"output"`
+	// The recipe unlocks once any of the user's pets reaches MinLevel and
+	// the user has unlocked Achievement (if set)
+	MinLevel    int    `json:"minLevel,omitempty"`
+	Achievement string `json:"achievement,omitempty"`
+}
+
+// RecipeOutput is either Quantity of a consumable Item or a Decoration
+type RecipeOutput struct {
+	Item       string `json:"item,omitempty"`
+	Decoration string `json:"decoration,omitempty"`
+	Quantity   int    `json:"quantity,omitempty"`
+}
+
+type recipeFile struct {
+	Version int      `json:"version"`
+	Recipes []Recipe `json:"recipes"`
+}
+
+func loadRecipes(data []byte) error {
+	var file recipeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("recipe catalog: %w", err)
+	}
+
+	byID := make(map[string]Recipe, len(file.Recipes))
+	ids := make([]string, 0, len(file.Recipes))
+	for _, r := range file.Recipes {
+		if r.ID == "" {
+			return fmt.Errorf("recipe catalog: recipe without id")
+		}
+		if _, dup := byID[r.ID]; dup {
+			return fmt.Errorf("recipe catalog: duplicate recipe %q", r.ID)
+		}
+		if len(r.Items) == 0 {
+			return fmt.Errorf("recipe catalog: recipe %q uses no items", r.ID)
+		}
+		for id, quantity := range r.Items {
+			if _, ok := FindItem(id); !ok || quantity <= 0 {
+				return fmt.Errorf("recipe catalog: recipe %q uses unknown item %q", r.ID, id)
+			}
+		}
+		// The fee is what makes retried crafts idempotent in the ledger
+		if r.Gold <= 0 {
+			return fmt.Errorf("recipe catalog: recipe %q needs a gold fee", r.ID)
+		}
+
+		out := &r.Output
+		if (out.Item == "") == (out.Decoration == "") {
+			return fmt.Errorf("recipe catalog: recipe %q needs either an item or a decoration", r.ID)
+		}
+		if out.Item != "" {
+			item, ok := FindItem(out.Item)
+			if !ok || !item.Consumable() {
+				return fmt.Errorf("recipe catalog: recipe %q makes unknown consumable %q", r.ID, out.Item)
+			}
+			if out.Quantity == 0 {
+				out.Quantity = 1
+			}
+		} else {
+			if _, ok := FindDecoration(out.Decoration); !ok {
+				return fmt.Errorf("recipe catalog: recipe %q makes unknown decoration %q", r.ID, out.Decoration)
+			}
+			out.Quantity = 1
+		}
+
+		if r.Achievement != "" {
+			if _, ok := FindAchievement(r.Achievement); !ok {
+				return fmt.Errorf("recipe catalog: recipe %q needs unknown achievement %q", r.ID, r.Achievement)
+			}
+		}
+		byID[r.ID] = r
+		ids = append(ids, r.ID)
+	}
+
+	mu.Lock()
+	recipes, recipeOrder = byID, ids
+	mu.Unlock()
+	return nil
+}
+
+// FindRecipe looks up a recipe by ID
+func FindRecipe(id string) (Recipe, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	r, ok := recipes[id]
+	return r, ok
+}
+
+// AllRecipes returns every recipe in catalog order
+func AllRecipes() []Recipe {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Recipe, len(recipeOrder))
+	for i, id := range recipeOrder {
+		out[i] = recipes[id]
+	}
+	return out
+}
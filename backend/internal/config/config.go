@@ -4,6 +4,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,79 @@ type Config struct {
 	JWTSecret   string
 	AppURL      string
 	Port        string
+
+	// Attachment storage
+	StorageDriver        string
+	StorageLocalPath     string
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKey          string
+	S3SecretKey          string
+	S3UseSSL             bool
+	AttachmentMaxBytes   int64
+	AttachmentQuotaBytes int64
+	// How often attachments left behind by deleted tasks are cleaned up
+	AttachmentCleanupInterval time.Duration
+
+	// Pet stat decay: every PetDecayInterval the pet loses the given points,
+	// never dropping below PetStatFloor
+	PetDecayInterval    time.Duration
+	PetHungerDecay      int
+	PetHappinessDecay   int
+	PetStatFloor        int
+	PetDecayJobInterval time.Duration
+
+	// Pet health: sickness, fainting and revival
+	PetSickAfter       time.Duration
+	PetFaintAfter      time.Duration
+	PetSickExpLoss     int
+	PetCureHunger      int
+	PetReviveHappiness int
+	PetReviveGoldCost  int
+	PetReviveQuests    int
+
+	// Pet levelling: leaving level L takes PetBaseExp * L^PetExpGrowth EXP
+	// (scaled per species); quests award PetExpPerReward EXP per gold of
+	// reward, clamped to [PetMinQuestExp, PetMaxQuestExp]
+	PetBaseExp      int
+	PetExpGrowth    float64
+	PetMaxLevel     int
+	PetExpPerReward float64
+	PetMinQuestExp  int
+	PetMaxQuestExp  int
+
+	// Pet interactions: each action has a cooldown, and repeating it within
+	// PetInteractionWindow multiplies its happiness boost by
+	// PetDiminishingFactor per earlier use
+	PetFeedCooldown      time.Duration
+	PetPlayCooldown      time.Duration
+	PetInteractionWindow time.Duration
+	PetDiminishingFactor float64
+
+	// Vacations pause pet decay; each lasts at most VacationMaxDays and a
+	// user may start VacationsPerYear of them in any 365 days
+	VacationMaxDays  int
+	VacationsPerYear int
+
+	// Streaks: a daily check-in pays StreakCheckInGold, and a user may hold
+	// up to StreakMaxFreezes freezes bought for StreakFreezePrice gold each
+	StreakCheckInGold int
+	StreakFreezePrice int
+	StreakMaxFreezes  int
+
+	// Gifts: a user may send GiftsPerDay gifts in any 24 hours, and send
+	// (or be sent) at most GiftGoldPerDay gold in them
+	GiftsPerDay    int
+	GiftGoldPerDay int
+
+	// LootSeed seeds the loot drop RNG so drops are reproducible; 0 seeds
+	// it from the clock
+	LootSeed int64
+
+	// DecorationCatalogPath, if set, is a decorations JSON file replacing
+	// the built-in shop catalog; it must not be an older version
+	DecorationCatalogPath string
 }
 
 func Load() *Config {
@@ -28,6 +103,59 @@ func Load() *Config {
 		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 		AppURL:      getEnv("APP_URL", "http://localhost:8080"),
 		Port:        getEnv("PORT", "8080"),
+
+		StorageDriver:        getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath:     getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		S3Endpoint:           getEnv("S3_ENDPOINT", "localhost:9000"),
+		S3Region:             getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:             getEnv("S3_BUCKET", "guildquest"),
+		S3AccessKey:          getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:          getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:             getEnvBool("S3_USE_SSL", false),
+		AttachmentMaxBytes:   getEnvInt64("ATTACHMENT_MAX_BYTES", 10<<20),
+		AttachmentQuotaBytes: getEnvInt64("ATTACHMENT_QUOTA_BYTES", 100<<20),
+
+		AttachmentCleanupInterval: getEnvDuration("ATTACHMENT_CLEANUP_INTERVAL", time.Hour),
+
+		PetDecayInterval:    getEnvDuration("PET_DECAY_INTERVAL", time.Hour),
+		PetHungerDecay:      int(getEnvInt64("PET_HUNGER_DECAY", 4)),
+		PetHappinessDecay:   int(getEnvInt64("PET_HAPPINESS_DECAY", 3)),
+		PetStatFloor:        int(getEnvInt64("PET_STAT_FLOOR", 0)),
+		PetDecayJobInterval: getEnvDuration("PET_DECAY_JOB_INTERVAL", 15*time.Minute),
+
+		PetSickAfter:       getEnvDuration("PET_SICK_AFTER", 12*time.Hour),
+		PetFaintAfter:      getEnvDuration("PET_FAINT_AFTER", 24*time.Hour),
+		PetSickExpLoss:     int(getEnvInt64("PET_SICK_EXP_LOSS", 5)),
+		PetCureHunger:      int(getEnvInt64("PET_CURE_HUNGER", 30)),
+		PetReviveHappiness: int(getEnvInt64("PET_REVIVE_HAPPINESS", 30)),
+		PetReviveGoldCost:  int(getEnvInt64("PET_REVIVE_GOLD_COST", 100)),
+		PetReviveQuests:    int(getEnvInt64("PET_REVIVE_QUESTS", 3)),
+
+		PetBaseExp:      int(getEnvInt64("PET_BASE_EXP", 100)),
+		PetExpGrowth:    getEnvFloat("PET_EXP_GROWTH", 1.0),
+		PetMaxLevel:     int(getEnvInt64("PET_MAX_LEVEL", 50)),
+		PetExpPerReward: getEnvFloat("PET_EXP_PER_REWARD", 1.0),
+		PetMinQuestExp:  int(getEnvInt64("PET_MIN_QUEST_EXP", 5)),
+		PetMaxQuestExp:  int(getEnvInt64("PET_MAX_QUEST_EXP", 100)),
+
+		PetFeedCooldown:      getEnvDuration("PET_FEED_COOLDOWN", time.Minute),
+		PetPlayCooldown:      getEnvDuration("PET_PLAY_COOLDOWN", 10*time.Minute),
+		PetInteractionWindow: getEnvDuration("PET_INTERACTION_WINDOW", 2*time.Hour),
+		PetDiminishingFactor: getEnvFloat("PET_DIMINISHING_FACTOR", 0.5),
+
+		VacationMaxDays:  int(getEnvInt64("VACATION_MAX_DAYS", 14)),
+		VacationsPerYear: int(getEnvInt64("VACATIONS_PER_YEAR", 3)),
+
+		StreakCheckInGold: int(getEnvInt64("STREAK_CHECK_IN_GOLD", 5)),
+		StreakFreezePrice: int(getEnvInt64("STREAK_FREEZE_PRICE", 50)),
+		StreakMaxFreezes:  int(getEnvInt64("STREAK_MAX_FREEZES", 2)),
+
+		GiftsPerDay:    int(getEnvInt64("GIFTS_PER_DAY", 10)),
+		GiftGoldPerDay: int(getEnvInt64("GIFT_GOLD_PER_DAY", 500)),
+
+		LootSeed: getEnvInt64("LOOT_SEED", 0),
+
+		DecorationCatalogPath: getEnv("DECORATION_CATALOG_PATH", ""),
 	}
 
 	// Validate required fields in production
@@ -46,3 +174,47 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("%s must be an integer: %v", key, err)
+		}
+		return n
+	}
+	return fallback
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Fatalf("%s must be a number: %v", key, err)
+		}
+		return f
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("%s must be a boolean: %v", key, err)
+		}
+		return b
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("%s must be a duration: %v", key, err)
+		}
+		return d
+	}
+	return fallback
+}
@@ -3,7 +3,9 @@ package database
 
 import (
 	"guildquest/internal/config"
+	"guildquest/internal/markdown"
 	"guildquest/internal/models"
+	"html/template"
 	"log"
 
 	"gorm.io/driver/postgres"
@@ -40,16 +42,108 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 func Migrate(db *gorm.DB) error {
 	log.Println("Running database migrations...")
 
+	if err := migratePetIDs(db); err != nil {
+		return err
+	}
+
 	// Run migrations in order
 	if err := db.AutoMigrate(
 		&models.User{},
+		&models.GoldTransaction{},
+		&models.UserAchievement{},
+		&models.Character{},
+		&models.Streak{},
+		&models.LootPity{},
 		&models.Task{},
+		&models.TaskStatus{},
 		&models.Pet{},
+		&models.PetHealthTransition{},
+		&models.PetEvent{},
+		&models.PetEquipment{},
+		&models.PetQuestTally{},
+		&models.PetEvolution{},
+		&models.InventoryItem{},
+		&models.PetInteraction{},
+		&models.PetItemCooldown{},
 		&models.Decoration{},
+		&models.LairLayout{},
+		&models.LairPlacement{},
+		&models.TimeEntry{},
+		&models.Attachment{},
+		&models.Vacation{},
+		&models.Gift{},
 	); err != nil {
 		return err
 	}
 
+	if err := backfillDescriptionHTML(db); err != nil {
+		return err
+	}
+
+	// Health transitions recorded before pets had their own IDs
+	if err := db.Exec(`UPDATE pet_health_transitions t SET pet_id = p.id
+		FROM pets p WHERE t.pet_id IS NULL AND p.user_id = t.user_id`).Error; err != nil {
+		return err
+	}
+
+	// Balances from before the ledger become each user's first entry
+	if err := db.Exec(`INSERT INTO gold_transactions (id, user_id, amount, balance, reason, created_at)
+		SELECT gen_random_uuid(), u.id, u.gold, u.gold, ?, NOW() FROM users u
+		WHERE u.gold <> 0 AND NOT EXISTS (SELECT 1 FROM gold_transactions g WHERE g.user_id = u.id)`,
+		models.GoldOpeningBalance).Error; err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
+
+// backfillDescriptionHTML renders descriptions of tasks created before
+// Markdown support existed
+func backfillDescriptionHTML(db *gorm.DB) error {
+	var tasks []models.Task
+	return db.Select("id", "description").
+		Where("description <> '' AND (description_html IS NULL OR description_html = '')").
+		FindInBatches(&tasks, 100, func(tx *gorm.DB, batch int) error {
+			for _, task := range tasks {
+				html, err := markdown.Render(task.Description)
+				if err != nil {
+					// Too long for the new limit: keep the source, escape it as text
+					html = "<p>" + template.HTMLEscapeString(task.Description) + "</p>"
+				}
+				if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).
+					UpdateColumn("description_html", html).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// migratePetIDs moves pets from a user_id primary key (one pet per user)
+// to their own id, keeping each existing pet as its owner's active pet
+func migratePetIDs(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Pet{}) || m.HasColumn(&models.Pet{}, "id") {
+		return nil
+	}
+
+	log.Println("Migrating pets to per-pet IDs...")
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range []string{
+			`ALTER TABLE pets ADD COLUMN id uuid`,
+			`UPDATE pets SET id = gen_random_uuid()`,
+			`ALTER TABLE pets DROP CONSTRAINT IF EXISTS pets_pkey`,
+			`ALTER TABLE pets ADD PRIMARY KEY (id)`,
+			`ALTER TABLE pets ADD COLUMN IF NOT EXISTS active boolean DEFAULT false`,
+			`UPDATE pets SET active = true`,
+			`ALTER TABLE pets ADD COLUMN IF NOT EXISTS created_at timestamptz`,
+			`UPDATE pets SET created_at = updated_at`,
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AchievementHandler struct {
+	achievementService services.AchievementService
+}
+
+func NewAchievementHandler(achievementService services.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+// GetAchievements godoc
+// @Summary Get achievements
+// @Description Every achievement with its reward, the user's progress toward it and when it was unlocked
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.AchievementResponse
+// @Router /me/achievements [get]
+func (h *AchievementHandler) GetAchievements(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	achievements, err := h.achievementService.GetAchievements(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, achievements)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService services.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// UploadAttachment godoc
+// @Summary Upload attachment
+// @Description Attach a file (image, PDF or text) to a task; images get a thumbnail
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param file formData file true "File"
+// @Success 201 {object} models.AttachmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	// Leave headroom for the multipart envelope
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxUploadBytes()+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "File is required",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid file",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+	defer file.Close()
+
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	attachment, err := h.attachmentService.Upload(userID, taskID, header.Filename, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "UPLOAD_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GetAttachments godoc
+// @Summary Get task attachments
+// @Description List attachments with short-lived signed download URLs
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.AttachmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	attachments, err := h.attachmentService.GetAttachments(userID, taskID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// DeleteAttachment godoc
+// @Summary Delete attachment
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /attachments/{id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	attachmentID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	if err := h.attachmentService.DeleteAttachment(userID, attachmentID); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "DELETE_FAILED",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadAttachment godoc
+// @Summary Download attachment
+// @Description Serve an attachment through a signed URL (no bearer token needed)
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param variant query string false "original or thumbnail"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} models.ErrorResponse
+// @Router /attachments/{id}/download [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	attachmentID := parseUUID(c.Param("id"))
+	variant := c.DefaultQuery("variant", "original")
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	attachment, body, err := h.attachmentService.OpenAttachment(attachmentID, variant, expires, c.Query("signature"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "DOWNLOAD_FAILED",
+		})
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	size := attachment.Size
+	if variant == "thumbnail" {
+		size = -1
+	}
+
+	c.DataFromReader(http.StatusOK, size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=300",
+	})
+}
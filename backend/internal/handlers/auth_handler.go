@@ -10,12 +10,16 @@ import (
 )
 
 type AuthHandler struct {
-	authService services.AuthService
+	authService      services.AuthService
+	streakService    services.StreakService
+	characterService services.CharacterService
 }
 
-func NewAuthHandler(authService services.AuthService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, streakService services.StreakService, characterService services.CharacterService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:      authService,
+		streakService:    streakService,
+		characterService: characterService,
 	}
 }
 
@@ -91,5 +95,57 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		return
 	}
 
+	streak, err := h.streakService.GetStreak(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	character, err := h.characterService.GetCharacter(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MeResponse{User: *user, Streak: streak, Character: character})
+}
+
+// SetTimeZone godoc
+// @Summary Set time zone
+// @Description Set the IANA time zone the user's days (for streaks and check-ins) are counted in
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TimeZoneRequest true "Time zone"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Router /me/timezone [put]
+func (h *AuthHandler) SetTimeZone(c *gin.Context) {
+	var req models.TimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	user, err := h.authService.SetTimeZone(userID, req.TimeZone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_TIME_ZONE",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, user)
 }
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CraftingHandler struct {
+	craftingService services.CraftingService
+}
+
+func NewCraftingHandler(craftingService services.CraftingService) *CraftingHandler {
+	return &CraftingHandler{craftingService: craftingService}
+}
+
+// GetRecipes godoc
+// @Summary Get recipes
+// @Description Every crafting recipe, with whether the user has unlocked it and can craft it now
+// @Tags crafting
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.RecipeResponse
+// @Router /crafting/recipes [get]
+func (h *CraftingHandler) GetRecipes(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	recipes, err := h.craftingService.GetRecipes(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipes)
+}
+
+// Craft godoc
+// @Summary Craft
+// @Description Use up a recipe's items and gold fee to make its item or decoration. Retries with the same Idempotency-Key don't craft again.
+// @Tags crafting
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key identifying this craft"
+// @Param request body models.CraftRequest true "Recipe"
+// @Success 200 {object} models.CraftResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /crafting/craft [post]
+func (h *CraftingHandler) Craft(c *gin.Context) {
+	var req models.CraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	result, err := h.craftingService.Craft(userID, req.Recipe, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "CRAFT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
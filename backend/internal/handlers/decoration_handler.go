@@ -42,7 +42,7 @@ func (h *DecorationHandler) GetDecorations(c *gin.Context) {
 
 // BuyDecoration godoc
 // @Summary Buy decoration
-// @Description Purchase a decoration (costs 50 gold)
+// @Description Purchase a catalog decoration or accessory at its catalog price
 // @Tags decorations
 // @Accept json
 // @Produce json
@@ -73,3 +73,27 @@ func (h *DecorationHandler) BuyDecoration(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Decoration purchased successfully"})
 }
+
+// GetShopDecorations godoc
+// @Summary List shop decorations
+// @Description Catalog decorations on sale now, with their category, rarity, price and level requirement
+// @Tags decorations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} catalog.Decoration
+// @Router /shop/decorations [get]
+func (h *DecorationHandler) GetShopDecorations(c *gin.Context) {
+	c.JSON(http.StatusOK, h.decorationService.GetShopDecorations())
+}
+
+// GetAccessories godoc
+// @Summary List pet accessories
+// @Description Accessories that can be bought as decorations and equipped on pets
+// @Tags decorations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} catalog.Accessory
+// @Router /shop/accessories [get]
+func (h *DecorationHandler) GetAccessories(c *gin.Context) {
+	c.JSON(http.StatusOK, h.decorationService.GetAccessories())
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GiftHandler struct {
+	giftService services.GiftService
+}
+
+func NewGiftHandler(giftService services.GiftService) *GiftHandler {
+	return &GiftHandler{giftService: giftService}
+}
+
+// SendGift godoc
+// @Summary Send gift
+// @Description Send gold, an item stack and/or a decoration to another user, who can accept or decline it. Retries with the same Idempotency-Key don't send again.
+// @Tags gifts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key identifying this gift"
+// @Param request body models.SendGiftRequest true "Gift"
+// @Success 201 {object} models.Gift
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /gifts [post]
+func (h *GiftHandler) SendGift(c *gin.Context) {
+	var req models.SendGiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	gift, err := h.giftService.SendGift(userID, req, c.GetHeader("Idempotency-Key"))
+	if errors.Is(err, services.ErrGiftKeyReused) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "IDEMPOTENCY_CONFLICT",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "GIFT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gift)
+}
+
+// GetInbox godoc
+// @Summary Get gift inbox
+// @Description Gifts waiting for the user to accept or decline, newest first
+// @Tags gifts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Gift
+// @Router /gifts/inbox [get]
+func (h *GiftHandler) GetInbox(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	gifts, err := h.giftService.GetInbox(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gifts)
+}
+
+// GetSentGifts godoc
+// @Summary Get sent gifts
+// @Description The user's most recently sent gifts and whether they were accepted
+// @Tags gifts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Gift
+// @Router /gifts/sent [get]
+func (h *GiftHandler) GetSentGifts(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	gifts, err := h.giftService.GetSentGifts(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gifts)
+}
+
+// AcceptGift godoc
+// @Summary Accept gift
+// @Tags gifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Gift ID"
+// @Success 200 {object} models.Gift
+// @Failure 400 {object} models.ErrorResponse
+// @Router /gifts/{id}/accept [post]
+func (h *GiftHandler) AcceptGift(c *gin.Context) {
+	giftID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	gift, err := h.giftService.AcceptGift(userID, giftID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "GIFT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gift)
+}
+
+// DeclineGift godoc
+// @Summary Decline gift
+// @Description Decline the gift, returning it to its sender
+// @Tags gifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Gift ID"
+// @Success 200 {object} models.Gift
+// @Failure 400 {object} models.ErrorResponse
+// @Router /gifts/{id}/decline [post]
+func (h *GiftHandler) DeclineGift(c *gin.Context) {
+	giftID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	gift, err := h.giftService.DeclineGift(userID, giftID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "GIFT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gift)
+}
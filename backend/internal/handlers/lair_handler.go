@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LairHandler struct {
+	lairService services.LairService
+}
+
+func NewLairHandler(lairService services.LairService) *LairHandler {
+	return &LairHandler{lairService: lairService}
+}
+
+// GetLayouts godoc
+// @Summary Get lair layouts
+// @Description The lair grid size and all of the user's saved layouts
+// @Tags lair
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.LairResponse
+// @Router /lair/layouts [get]
+func (h *LairHandler) GetLayouts(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	lair, err := h.lairService.GetLayouts(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, lair)
+}
+
+// GetLayout godoc
+// @Summary Get lair layout
+// @Tags lair
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Success 200 {object} models.LairLayout
+// @Failure 404 {object} models.ErrorResponse
+// @Router /lair/layouts/{id} [get]
+func (h *LairHandler) GetLayout(c *gin.Context) {
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	layout, err := h.lairService.GetLayout(userID, layoutID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "LAYOUT_NOT_FOUND",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// CreateLayout godoc
+// @Summary Create lair layout
+// @Description Save a new named layout; the first layout becomes the active one
+// @Tags lair
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.LairLayoutRequest true "Layout"
+// @Success 201 {object} models.LairLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts [post]
+func (h *LairHandler) CreateLayout(c *gin.Context) {
+	var req models.LairLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	layout, err := h.lairService.CreateLayout(userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "LAYOUT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, layout)
+}
+
+// SaveLayout godoc
+// @Summary Save lair layout
+// @Description Rename a layout and replace all of its placements
+// @Tags lair
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Param request body models.LairLayoutRequest true "Layout"
+// @Success 200 {object} models.LairLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts/{id} [put]
+func (h *LairHandler) SaveLayout(c *gin.Context) {
+	var req models.LairLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	layout, err := h.lairService.SaveLayout(userID, layoutID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "LAYOUT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// DeleteLayout godoc
+// @Summary Delete lair layout
+// @Tags lair
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts/{id} [delete]
+func (h *LairHandler) DeleteLayout(c *gin.Context) {
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	if err := h.lairService.DeleteLayout(userID, layoutID); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "LAYOUT_FAILED",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateLayout godoc
+// @Summary Activate lair layout
+// @Description Make the layout the one shown in the lair
+// @Tags lair
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Success 200 {object} models.LairLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts/{id}/activate [post]
+func (h *LairHandler) ActivateLayout(c *gin.Context) {
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	layout, err := h.lairService.ActivateLayout(userID, layoutID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "LAYOUT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// PlaceDecoration godoc
+// @Summary Place decoration
+// @Description Place an owned decoration in the layout, or move and rotate it if it is already placed
+// @Tags lair
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Param request body models.PlaceDecorationRequest true "Placement"
+// @Success 200 {object} models.LairLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts/{id}/placements [post]
+func (h *LairHandler) PlaceDecoration(c *gin.Context) {
+	var req models.PlaceDecorationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	layout, err := h.lairService.PlaceDecoration(userID, layoutID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "PLACEMENT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// RemoveDecoration godoc
+// @Summary Remove decoration
+// @Description Take a decoration out of the layout; the user keeps owning it
+// @Tags lair
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Layout ID"
+// @Param decoration path string true "Decoration ID"
+// @Success 200 {object} models.LairLayout
+// @Failure 400 {object} models.ErrorResponse
+// @Router /lair/layouts/{id}/placements/{decoration} [delete]
+func (h *LairHandler) RemoveDecoration(c *gin.Context) {
+	layoutID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	layout, err := h.lairService.RemoveDecoration(userID, layoutID, c.Param("decoration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "PLACEMENT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	ledgerService services.LedgerService
+}
+
+func NewLedgerHandler(ledgerService services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// GetTransactions godoc
+// @Summary Get gold transactions
+// @Description Gold history, newest first. Page with before set to the oldest created_at seen.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum transactions (1-200, default 50)"
+// @Param before query string false "Only transactions before this RFC 3339 time"
+// @Success 200 {array} models.GoldTransaction
+// @Failure 400 {object} models.ErrorResponse
+// @Router /me/transactions [get]
+func (h *LedgerHandler) GetTransactions(c *gin.Context) {
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "limit must be between 1 and 200",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+		limit = n
+	}
+
+	var before time.Time
+	if v := c.Query("before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "before must be an RFC 3339 time",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+		before = t
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	transactions, err := h.ledgerService.GetTransactions(userID, before, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"guildquest/internal/models"
 	"guildquest/internal/services"
@@ -22,8 +23,8 @@ func (h *PetHandler) FeedPet(c *gin.Context) {
 
 	pet, err := h.petService.FeedPet(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: "Feed failed",
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
 			Code:  "FEED_FAILED",
 		})
 		return
@@ -37,8 +38,8 @@ func (h *PetHandler) PlayWithPet(c *gin.Context) {
 
 	pet, err := h.petService.PlayWithPet(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: "Play failed",
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
 			Code:  "PLAY_FAILED",
 		})
 		return
@@ -67,3 +68,358 @@ func (h *PetHandler) GetPet(c *gin.Context) {
 
 	c.JSON(http.StatusOK, pet)
 }
+
+// GetPetHealth godoc
+// @Summary Get pet health
+// @Description Current health state, revival requirements and recent health transitions
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PetHealthResponse
+// @Router /pet/health [get]
+func (h *PetHandler) GetPetHealth(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	health, err := h.petService.GetHealth(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, health)
+}
+
+// RevivePet godoc
+// @Summary Revive fainted pet
+// @Description Revive a fainted pet by paying gold or after completing enough quests. Retries with the same Idempotency-Key don't charge again.
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key identifying this revival"
+// @Param request body models.RevivePetRequest true "Revival method"
+// @Success 200 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pet/revive [post]
+func (h *PetHandler) RevivePet(c *gin.Context) {
+	var req models.RevivePetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	pet, err := h.petService.RevivePet(userID, req.Method, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "REVIVE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pet)
+}
+
+// GetPets godoc
+// @Summary List pets
+// @Description All pets owned by the user; exactly one is active
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Pet
+// @Router /pets [get]
+func (h *PetHandler) GetPets(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	pets, err := h.petService.GetPets(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pets)
+}
+
+// GetSpecies godoc
+// @Summary List pet species
+// @Description Species catalog with stat modifiers and adoption requirements
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} catalog.Species
+// @Router /species [get]
+func (h *PetHandler) GetSpecies(c *gin.Context) {
+	c.JSON(http.StatusOK, h.petService.GetSpecies())
+}
+
+// AdoptPet godoc
+// @Summary Adopt pet
+// @Description Adopt a new species by paying gold or reaching its milestones. Retries with the same Idempotency-Key don't charge again.
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key identifying this adoption"
+// @Param request body models.AdoptPetRequest true "Adoption"
+// @Success 201 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/adopt [post]
+func (h *PetHandler) AdoptPet(c *gin.Context) {
+	var req models.AdoptPetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	pet, err := h.petService.AdoptPet(userID, req, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "ADOPT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pet)
+}
+
+// ActivatePet godoc
+// @Summary Set active pet
+// @Description Make this pet the active companion that earns quest rewards
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Pet ID"
+// @Success 200 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/{id}/activate [post]
+func (h *PetHandler) ActivatePet(c *gin.Context) {
+	petID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	pet, err := h.petService.ActivatePet(userID, petID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "ACTIVATE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pet)
+}
+
+// RenamePet godoc
+// @Summary Rename pet
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Pet ID"
+// @Param request body models.RenamePetRequest true "New name"
+// @Success 200 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/{id} [patch]
+func (h *PetHandler) RenamePet(c *gin.Context) {
+	var req models.RenamePetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	petID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	pet, err := h.petService.RenamePet(userID, petID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "RENAME_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pet)
+}
+
+// GetPetEvolution godoc
+// @Summary Get pet evolution
+// @Description Current form, quest category tallies, the next evolution and the evolution history
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Pet ID"
+// @Success 200 {object} models.PetEvolutionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/{id}/evolution [get]
+func (h *PetHandler) GetPetEvolution(c *gin.Context) {
+	petID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	evolution, err := h.petService.GetEvolution(userID, petID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, evolution)
+}
+
+// UseItem godoc
+// @Summary Use item on pet
+// @Description Feed, play with or treat the active pet using an inventory item
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UseItemRequest true "Item to use"
+// @Success 200 {object} models.UseItemResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pet/use-item [post]
+func (h *PetHandler) UseItem(c *gin.Context) {
+	var req models.UseItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	result, err := h.petService.UseItem(userID, req.ItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "USE_ITEM_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPetEvents godoc
+// @Summary Get pet event log
+// @Description Recent events of the active pet, newest first
+// @Tags pet
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum events (default 50, max 200)"
+// @Success 200 {array} models.PetEvent
+// @Router /pet/events [get]
+func (h *PetHandler) GetPetEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid limit",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	events, err := h.petService.GetEvents(userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// EquipAccessory godoc
+// @Summary Equip accessory
+// @Description Put an owned accessory in its slot on the pet, replacing what was there
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Pet ID"
+// @Param request body models.EquipAccessoryRequest true "Accessory"
+// @Success 200 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/{id}/equip [post]
+func (h *PetHandler) EquipAccessory(c *gin.Context) {
+	var req models.EquipAccessoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	petID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	pet, err := h.petService.EquipAccessory(userID, petID, req.AccessoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "EQUIP_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pet)
+}
+
+// UnequipAccessory godoc
+// @Summary Unequip accessory
+// @Tags pet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Pet ID"
+// @Param request body models.UnequipAccessoryRequest true "Slot to clear"
+// @Success 200 {object} models.Pet
+// @Failure 400 {object} models.ErrorResponse
+// @Router /pets/{id}/unequip [post]
+func (h *PetHandler) UnequipAccessory(c *gin.Context) {
+	var req models.UnequipAccessoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	petID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	pet, err := h.petService.UnequipAccessory(userID, petID, req.Slot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "UNEQUIP_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pet)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShopHandler struct {
+	itemService services.ItemService
+}
+
+func NewShopHandler(itemService services.ItemService) *ShopHandler {
+	return &ShopHandler{itemService: itemService}
+}
+
+// GetItems godoc
+// @Summary List shop items
+// @Description Foods, toys and potions with their prices, effects and cooldowns
+// @Tags shop
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} catalog.Item
+// @Router /shop/items [get]
+func (h *ShopHandler) GetItems(c *gin.Context) {
+	c.JSON(http.StatusOK, h.itemService.GetShopItems())
+}
+
+// BuyItem godoc
+// @Summary Buy item
+// @Description Buy one or more of an item; toys can only be owned once. Retries with the same Idempotency-Key don't buy again.
+// @Tags shop
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param Idempotency-Key header string false "Key identifying this purchase"
+// @Param request body models.BuyItemRequest false "Quantity (default 1)"
+// @Success 200 {object} models.InventoryItem
+// @Failure 400 {object} models.ErrorResponse
+// @Router /shop/items/{id}/buy [post]
+func (h *ShopHandler) BuyItem(c *gin.Context) {
+	var req models.BuyItemRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request body",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	item, err := h.itemService.BuyItem(userID, c.Param("id"), req.Quantity, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "PURCHASE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// GetInventory godoc
+// @Summary Get inventory
+// @Description Items owned by the user
+// @Tags shop
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.InventoryItem
+// @Router /inventory [get]
+func (h *ShopHandler) GetInventory(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	items, err := h.itemService.GetInventory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
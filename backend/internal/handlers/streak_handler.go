@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StreakHandler struct {
+	streakService services.StreakService
+}
+
+func NewStreakHandler(streakService services.StreakService) *StreakHandler {
+	return &StreakHandler{streakService: streakService}
+}
+
+// CheckIn godoc
+// @Summary Daily check-in
+// @Description Claim the daily check-in reward, once per day in the user's time zone
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CheckInResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /me/check-in [post]
+func (h *StreakHandler) CheckIn(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	resp, err := h.streakService.CheckIn(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "CHECK_IN_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// BuyFreeze godoc
+// @Summary Buy streak freeze
+// @Description Buy a freeze that covers one day without a completed quest. Retries with the same Idempotency-Key don't buy again.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key identifying this purchase"
+// @Success 200 {object} models.StreakResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /me/streak/freezes [post]
+func (h *StreakHandler) BuyFreeze(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	streak, err := h.streakService.BuyFreeze(userID, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "PURCHASE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, streak)
+}
@@ -105,11 +105,12 @@ func (h *TaskHandler) CreateBulkTasks(c *gin.Context) {
 
 // CompleteTask godoc
 // @Summary Complete task
+// @Description Complete the task; the response lists any loot the quest dropped
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Task ID"
-// @Success 200 {object} models.Task
+// @Success 200 {object} models.CompleteTaskResponse
 // @Router /tasks/{id}/complete [post]
 func (h *TaskHandler) CompleteTask(c *gin.Context) {
 	taskID := parseUUID(c.Param("id"))
@@ -149,3 +150,267 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// MoveTask godoc
+// @Summary Move task
+// @Description Move a task to a board column, between beforeId (above) and afterId (below). Entering a done column completes the task.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param request body models.MoveTaskRequest true "Target column and neighbours"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/move [post]
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	var req models.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	task, err := h.taskService.MoveTask(userID, taskID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "MOVE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// GetCategories godoc
+// @Summary Get quest categories
+// @Description Categories a task can be tagged with; they steer pet evolution
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} catalog.Category
+// @Router /categories [get]
+func (h *TaskHandler) GetCategories(c *gin.Context) {
+	c.JSON(http.StatusOK, h.taskService.GetCategories())
+}
+
+// GetStatuses godoc
+// @Summary Get board columns
+// @Tags statuses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TaskStatus
+// @Router /statuses [get]
+func (h *TaskHandler) GetStatuses(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	statuses, err := h.taskService.GetStatuses(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// CreateStatus godoc
+// @Summary Create board column
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTaskStatusRequest true "Column"
+// @Success 201 {object} models.TaskStatus
+// @Failure 400 {object} models.ErrorResponse
+// @Router /statuses [post]
+func (h *TaskHandler) CreateStatus(c *gin.Context) {
+	var req models.CreateTaskStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	status, err := h.taskService.CreateStatus(userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Create failed",
+			Code:  "CREATE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+// UpdateStatus godoc
+// @Summary Update board column
+// @Description Rename, reorder or change the terminal flag of an empty column
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Status ID"
+// @Param request body models.UpdateTaskStatusRequest true "Changes"
+// @Success 200 {object} models.TaskStatus
+// @Failure 400 {object} models.ErrorResponse
+// @Router /statuses/{id} [patch]
+func (h *TaskHandler) UpdateStatus(c *gin.Context) {
+	var req models.UpdateTaskStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	statusID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	status, err := h.taskService.UpdateStatus(userID, statusID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "UPDATE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// DeleteStatus godoc
+// @Summary Delete board column
+// @Description Delete an empty column; the board keeps at least one open and one done column
+// @Tags statuses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Status ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /statuses/{id} [delete]
+func (h *TaskHandler) DeleteStatus(c *gin.Context) {
+	statusID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	if err := h.taskService.DeleteStatus(userID, statusID); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "DELETE_FAILED",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPendingReviews godoc
+// @Summary Get tasks awaiting my review
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Task
+// @Router /reviews [get]
+func (h *TaskHandler) GetPendingReviews(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	tasks, err := h.taskService.GetPendingReviews(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+// ApproveTask godoc
+// @Summary Approve task
+// @Description Approve a task awaiting review; completes it and pays out the owner's rewards
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param request body models.ReviewTaskRequest false "Review comment"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/approve [post]
+func (h *TaskHandler) ApproveTask(c *gin.Context) {
+	var req models.ReviewTaskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	task, err := h.taskService.ApproveTask(userID, taskID, req.Comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "APPROVE_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// RejectTask godoc
+// @Summary Reject task
+// @Description Send a task back to its owner with a comment; no rewards are granted
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param request body models.ReviewTaskRequest true "Review comment"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/reject [post]
+func (h *TaskHandler) RejectTask(c *gin.Context) {
+	var req models.ReviewTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	task, err := h.taskService.RejectTask(userID, taskID, req.Comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "REJECT_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimeHandler struct {
+	timeTrackingService services.TimeTrackingService
+}
+
+func NewTimeHandler(timeTrackingService services.TimeTrackingService) *TimeHandler {
+	return &TimeHandler{timeTrackingService: timeTrackingService}
+}
+
+// StartTimer godoc
+// @Summary Start timer
+// @Description Start a timer or Pomodoro session on a task (one running timer per user)
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param request body models.StartTimerRequest false "Timer options"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/timer/start [post]
+func (h *TimeHandler) StartTimer(c *gin.Context) {
+	var req models.StartTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	entry, err := h.timeTrackingService.StartTimer(userID, taskID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "TIMER_START_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// StopTimer godoc
+// @Summary Stop timer
+// @Description Stop the running timer; completed Pomodoro sessions award bonus pet EXP
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Router /timer/stop [post]
+func (h *TimeHandler) StopTimer(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	entry, err := h.timeTrackingService.StopTimer(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "TIMER_STOP_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetActiveTimer godoc
+// @Summary Get running timer
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 404 {object} models.ErrorResponse
+// @Router /timer [get]
+func (h *TimeHandler) GetActiveTimer(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	entry, err := h.timeTrackingService.GetActiveTimer(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "NO_ACTIVE_TIMER",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetTaskTime godoc
+// @Summary Get time logged on a task
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TaskTimeTotal
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tasks/{id}/time [get]
+func (h *TimeHandler) GetTaskTime(c *gin.Context) {
+	taskID := parseUUID(c.Param("id"))
+	userID := parseUUID(c.GetString("userID"))
+
+	total, err := h.timeTrackingService.GetTaskTotal(userID, taskID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, total)
+}
+
+// GetDailyTime godoc
+// @Summary Get time logged per day
+// @Description Daily totals (UTC) between from and to inclusive, defaulting to the last 7 days
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} models.DailyTimeTotal
+// @Failure 400 {object} models.ErrorResponse
+// @Router /time/daily [get]
+func (h *TimeHandler) GetDailyTime(c *gin.Context) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -6)
+	to := today
+
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid from date",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid to date",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	totals, err := h.timeTrackingService.GetDailyTotals(userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"guildquest/internal/models"
+	"guildquest/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VacationHandler struct {
+	vacationService services.VacationService
+}
+
+func NewVacationHandler(vacationService services.VacationService) *VacationHandler {
+	return &VacationHandler{vacationService: vacationService}
+}
+
+// GetVacation godoc
+// @Summary Get vacation
+// @Description Current or upcoming vacation, recent vacations and how many are left this year
+// @Tags vacation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.VacationResponse
+// @Router /vacation [get]
+func (h *VacationHandler) GetVacation(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	vacation, err := h.vacationService.GetVacation(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Fetch failed",
+			Code:  "FETCH_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, vacation)
+}
+
+// StartVacation godoc
+// @Summary Schedule vacation
+// @Description Pause pet decay and health timers between two dates (inclusive, UTC)
+// @Tags vacation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.StartVacationRequest true "Vacation dates"
+// @Success 201 {object} models.Vacation
+// @Failure 400 {object} models.ErrorResponse
+// @Router /vacation [post]
+func (h *VacationHandler) StartVacation(c *gin.Context) {
+	var req models.StartVacationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	userID := parseUUID(c.GetString("userID"))
+	vacation, err := h.vacationService.StartVacation(userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "VACATION_FAILED",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, vacation)
+}
+
+// EndVacation godoc
+// @Summary End vacation
+// @Description End the current vacation early or cancel an upcoming one
+// @Tags vacation
+// @Produce json
+// @Security BearerAuth
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Router /vacation [delete]
+func (h *VacationHandler) EndVacation(c *gin.Context) {
+	userID := parseUUID(c.GetString("userID"))
+
+	if err := h.vacationService.EndVacation(userID); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+			Code:  "VACATION_FAILED",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
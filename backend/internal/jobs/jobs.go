@@ -0,0 +1,70 @@
+// internal/jobs/jobs.go
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"guildquest/internal/config"
+	"guildquest/internal/repositories"
+	"guildquest/internal/services"
+	"guildquest/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// Start launches background jobs; they stop when ctx is cancelled
+func Start(ctx context.Context, db *gorm.DB, store storage.BlobStore, cfg *config.Config) {
+	petRepo := repositories.NewPetRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
+
+	taskRepo := repositories.NewTaskRepository(db)
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	decorationRepo := repositories.NewDecorationRepository(db)
+	vacationRepo := repositories.NewVacationRepository(db)
+
+	petService := services.NewPetService(petRepo, ledgerRepo, taskRepo, inventoryRepo, decorationRepo, vacationRepo, repositories.NewUnitOfWork(db), services.NewPetConfig(cfg))
+
+	go runEvery(ctx, "pet decay", cfg.PetDecayJobInterval, func() error {
+		n, err := petService.DecayAll()
+		if n > 0 {
+			log.Printf("pet decay: updated %d pets", n)
+		}
+		return err
+	})
+
+	attachmentService := services.NewAttachmentService(
+		repositories.NewAttachmentRepository(db), taskRepo, repositories.NewUnitOfWork(db), store, storage.NewURLSigner(cfg.JWTSecret),
+		cfg.AppURL, cfg.AttachmentMaxBytes, cfg.AttachmentQuotaBytes,
+	)
+
+	go runEvery(ctx, "attachment cleanup", cfg.AttachmentCleanupInterval, func() error {
+		n, err := attachmentService.PurgeOrphans()
+		if n > 0 {
+			log.Printf("attachment cleanup: deleted %d orphaned attachments", n)
+		}
+		return err
+	})
+}
+
+func runEvery(ctx context.Context, name string, interval time.Duration, fn func() error) {
+	if interval <= 0 {
+		log.Printf("job %s disabled", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+		}
+	}
+}
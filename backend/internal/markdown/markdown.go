@@ -0,0 +1,63 @@
+// internal/markdown/markdown.go
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// MaxSourceLength is the longest description (in characters) we accept
+const MaxSourceLength = 10000
+
+var (
+	// CommonMark plus GFM task lists and bare-URL autolinks. Raw HTML in
+	// the source is dropped by goldmark's default (unsafe off) renderer.
+	renderer = goldmark.New(
+		goldmark.WithExtensions(
+			extension.TaskList,
+			extension.Linkify,
+		),
+	)
+
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Task list checkboxes as rendered by goldmark
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Links: only web and mail schemes, opened outside the app
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return p
+}
+
+// Render converts Markdown source to sanitized HTML
+func Render(source string) (string, error) {
+	if utf8.RuneCountInString(source) > MaxSourceLength {
+		return "", errors.New("description is too long")
+	}
+	if source == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,70 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFormatting(t *testing.T) {
+	for source, want := range map[string]string{
+		"**bold** and _em_":       "<p><strong>bold</strong> and <em>em</em></p>",
+		"- [x] done\n- [ ] todo":  `<input checked="" disabled="" type="checkbox"`,
+		"`code`":                  "<code>code</code>",
+		"see https://example.com": `<a href="https://example.com" rel="nofollow noreferrer noopener" target="_blank">https://example.com</a>`,
+	} {
+		html, err := Render(source)
+		if err != nil {
+			t.Fatalf("Render(%q): %v", source, err)
+		}
+		if !strings.Contains(html, want) {
+			t.Errorf("Render(%q) = %q, want it to contain %q", source, html, want)
+		}
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	for source, banned := range map[string]string{
+		"<script>alert(1)</script>":                             "<script",
+		"<img src=x onerror=alert(1)>":                          "onerror",
+		"[click](javascript:alert(1))":                          "javascript:",
+		"[data](data:text/html;base64,PHNjcmlwdD4=)":            "data:",
+		`<a href="https://example.com" onclick="steal()">x</a>`: "onclick",
+		"<p style=\"position:fixed\">x</p>":                     "style=",
+		"<iframe src=\"https://evil.example\"></iframe>":        "<iframe",
+	} {
+		html, err := Render(source)
+		if err != nil {
+			t.Fatalf("Render(%q): %v", source, err)
+		}
+		if strings.Contains(strings.ToLower(html), banned) {
+			t.Errorf("Render(%q) = %q, which still contains %q", source, html, banned)
+		}
+	}
+}
+
+func TestRenderLength(t *testing.T) {
+	if html, err := Render(""); err != nil || html != "" {
+		t.Errorf("Render(\"\") = %q, %v; want empty", html, err)
+	}
+
+	// The limit counts characters, not bytes
+	if _, err := Render(strings.Repeat("é", MaxSourceLength)); err != nil {
+		t.Errorf("rejected %d characters: %v", MaxSourceLength, err)
+	}
+	if _, err := Render(strings.Repeat("a", MaxSourceLength+1)); err == nil {
+		t.Errorf("accepted %d characters", MaxSourceLength+1)
+	}
+}
+
+// The policy is the backstop should the renderer ever let HTML through
+func TestPolicyStripsUnsafeHTML(t *testing.T) {
+	html := policy.Sanitize(`<a href="javascript:alert(1)">a</a><img src="x" onerror="y"><input type="text" value="v"><input type="checkbox" checked disabled>`)
+	for _, banned := range []string{"javascript:", "onerror", `type="text"`} {
+		if strings.Contains(html, banned) {
+			t.Errorf("sanitized HTML %q still contains %q", html, banned)
+		}
+	}
+	if !strings.Contains(html, `type="checkbox"`) {
+		t.Errorf("sanitized HTML %q lost the task list checkbox", html)
+	}
+}
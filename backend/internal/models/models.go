@@ -14,8 +14,11 @@ type User struct {
 	Email        string    `gorm:"unique;not null" json:"email"`
 	PasswordHash string    `gorm:"not null" json:"-"`
 	Gold         int       `gorm:"default:0" json:"gold"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	// TimeZone is an IANA zone name; the user's days (for streaks) run
+	// from midnight to midnight there
+	TimeZone  string    `gorm:"not null;default:'UTC'" json:"timeZone"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -25,16 +28,148 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// GoldTransaction is an entry in a user's append-only gold ledger. User.Gold
+// always equals the sum of the user's entries; Balance is the balance right
+// after this one was applied.
+type GoldTransaction struct {
+	ID      uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_gold_transactions_idempotency" json:"userId"`
+	Amount  int       `gorm:"not null" json:"amount"`
+	Balance int       `gorm:"not null" json:"balance"`
+	Reason  string    `gorm:"not null" json:"reason"`
+	// RefType and RefID point at what the gold was for ("task", "item", ...)
+	RefType        string    `json:"refType,omitempty"`
+	RefID          string    `json:"refId,omitempty"`
+	IdempotencyKey *string   `gorm:"uniqueIndex:idx_gold_transactions_idempotency" json:"-"`
+	CreatedAt      time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (t *GoldTransaction) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// Gold ledger reasons
+const (
+	GoldQuestReward    = "quest_reward"
+	GoldItemPurchase   = "item_purchase"
+	GoldDecoration     = "decoration_purchase"
+	GoldPetAdoption    = "pet_adoption"
+	GoldPetRevival     = "pet_revival"
+	GoldAchievement    = "achievement_reward"
+	GoldStreakBonus    = "streak_bonus"
+	GoldStreakFreeze   = "streak_freeze"
+	GoldCheckIn        = "check_in"
+	GoldLoot           = "loot_drop"
+	GoldCrafting       = "crafting_fee"
+	GoldAttributeBonus = "attribute_bonus"
+	GoldGiftSent       = "gift_sent"
+	GoldGiftReceived   = "gift_received"
+	GoldGiftReturned   = "gift_returned"
+	GoldOpeningBalance = "opening_balance"
+)
+
+// UserAchievement records when the user unlocked a catalog achievement
+type UserAchievement struct {
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	AchievementID string    `gorm:"primaryKey" json:"achievementId"`
+	UnlockedAt    time.Time `gorm:"not null" json:"unlockedAt"`
+}
+
+// Streak is the user's run of consecutive days with at least one
+// completed quest. Days are dates (YYYY-MM-DD) in the user's time zone.
+type Streak struct {
+	UserID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Current int       `gorm:"not null;default:0" json:"current"`
+	Longest int       `gorm:"not null;default:0" json:"longest"`
+	// LastDay is the latest day the streak covers, whether by a quest, a
+	// vacation or a freeze
+	LastDay     string    `json:"lastDay,omitempty"`
+	Freezes     int       `gorm:"not null;default:0" json:"freezes"`
+	LastCheckIn string    `json:"lastCheckIn,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Character is the user's own progression, separate from their pets.
+// Quests earn XP, and their categories grow the attributes.
+type Character struct {
+	UserID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Level        int       `gorm:"not null;default:1" json:"level"`
+	Exp          int       `gorm:"not null;default:0" json:"exp"`
+	Strength     int       `gorm:"not null;default:0" json:"strength"`
+	Intelligence int       `gorm:"not null;default:0" json:"intelligence"`
+	Constitution int       `gorm:"not null;default:0" json:"constitution"`
+	Perception   int       `gorm:"not null;default:0" json:"perception"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Gift statuses
+const (
+	GiftPending  = "pending"
+	GiftAccepted = "accepted"
+	GiftDeclined = "declined"
+)
+
+// Gift is gold, an item stack and/or a decoration one user sends another,
+// with a message. It leaves the sender when sent and reaches the
+// recipient once they accept it; a declined gift goes back to the sender.
+type Gift struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	SenderID       uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_gifts_idempotency" json:"senderId"`
+	SenderEmail    string     `gorm:"not null" json:"senderEmail"`
+	RecipientID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"recipientId"`
+	RecipientEmail string     `gorm:"not null" json:"recipientEmail"`
+	Gold           int        `gorm:"not null;default:0" json:"gold"`
+	ItemID         string     `json:"itemId,omitempty"`
+	Quantity       int        `gorm:"not null;default:0" json:"quantity,omitempty"`
+	Decoration     string     `json:"decoration,omitempty"`
+	Message        string     `json:"message"`
+	Status         string     `gorm:"not null;default:'pending';index" json:"status"`
+	RespondedAt    *time.Time `json:"respondedAt"`
+	IdempotencyKey *string    `gorm:"uniqueIndex:idx_gifts_idempotency" json:"-"`
+	CreatedAt      time.Time  `gorm:"index" json:"createdAt"`
+}
+
+func (g *Gift) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// LootPity counts the user's quests since their last rare loot drop
+type LootPity struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	SinceRare int       `gorm:"not null;default:0" json:"sinceRare"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
 // Task represents a quest/task
 type Task struct {
-	ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
-	Title       string    `gorm:"not null" json:"title"`
-	Description string    `json:"description"`
-	Reward      int       `gorm:"default:10" json:"reward"`
-	Completed   bool      `gorm:"default:false" json:"completed"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Title           string     `gorm:"not null" json:"title"`
+	Description     string     `json:"description"`
+	DescriptionHTML string     `gorm:"column:description_html" json:"descriptionHtml"`
+	Category        string     `gorm:"index" json:"category"`
+	Reward          int        `gorm:"default:10" json:"reward"`
+	Completed       bool       `gorm:"default:false" json:"completed"`
+	StatusID        *uuid.UUID `gorm:"type:uuid;index" json:"statusId"`
+	Rank            float64    `gorm:"default:0" json:"rank"`
+	CompletedAt     *time.Time `json:"completedAt"`
+	RewardedAt      *time.Time `json:"rewardedAt"`
+
+	// Approval workflow: rewards wait for the reviewer's approval
+	RequiresApproval bool       `gorm:"default:false" json:"requiresApproval"`
+	ReviewerID       *uuid.UUID `gorm:"type:uuid;index" json:"reviewerId"`
+	ReviewStatus     string     `json:"reviewStatus"`
+	ReviewComment    string     `json:"reviewComment"`
+	ReviewedAt       *time.Time `json:"reviewedAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 func (t *Task) BeforeCreate(tx *gorm.DB) error {
@@ -44,17 +179,197 @@ func (t *Task) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// Pet represents the user's virtual pet
+// Attachment represents a file attached to a task
+type Attachment struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	TaskID       uuid.UUID `gorm:"type:uuid;not null;index" json:"taskId"`
+	FileName     string    `gorm:"not null" json:"fileName"`
+	ContentType  string    `gorm:"not null" json:"contentType"`
+	Size         int64     `gorm:"not null" json:"size"`
+	StorageKey   string    `gorm:"not null" json:"-"`
+	ThumbnailKey string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// Task review states
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// TaskStatus represents a user's board column (backlog, in progress, ...)
+type TaskStatus struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Name       string    `gorm:"not null" json:"name"`
+	Position   int       `gorm:"default:0" json:"position"`
+	IsTerminal bool      `gorm:"default:false" json:"isTerminal"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (s *TaskStatus) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// Pet represents one of the user's virtual pets. Type is the species ID;
+// exactly one pet per user is the active companion.
 type Pet struct {
-	UserID    uuid.UUID `gorm:"type:uuid;primary_key" json:"userId"`
-	Type      string    `gorm:"default:'dragon'" json:"type"`
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_pets_active_user,where:active;uniqueIndex:idx_pets_user_type" json:"userId"`
+	Name      string    `json:"name"`
+	Type      string    `gorm:"default:'dragon';uniqueIndex:idx_pets_user_type" json:"type"`
+	Form      string    `json:"form"`
+	Active    bool      `gorm:"default:false" json:"active"`
 	Level     int       `gorm:"default:1" json:"level"`
 	Exp       int       `gorm:"default:0" json:"exp"`
 	Hunger    int       `gorm:"default:100" json:"hunger"`
 	Happiness int       `gorm:"default:100" json:"happiness"`
+	// Health is "healthy", "sick" or "fainted"
+	Health          string     `gorm:"default:'healthy'" json:"health"`
+	HealthChangedAt *time.Time `json:"healthChangedAt"`
+	StarvingSince   *time.Time `json:"starvingSince"`
+	RevivalProgress int        `gorm:"default:0" json:"revivalProgress"`
+	// Mood is derived from the pet's stats and recent quests; it is stored
+	// so changes can be logged as events
+	Mood        string     `gorm:"default:'content'" json:"mood"`
+	LastQuestAt *time.Time `json:"lastQuestAt"`
+	// DecayedAt is the point up to which stat decay has been applied
+	DecayedAt *time.Time `json:"-"`
+	// HungerCarry and HappinessCarry are fractions of a point of decay due
+	// but not yet taken, so fractional rates add up the same however often
+	// the pet is read
+	HungerCarry    float64   `gorm:"default:0" json:"-"`
+	HappinessCarry float64   `gorm:"default:0" json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+
+	// NextAvailableAt maps interactions still on cooldown to when they can
+	// be used again
+	NextAvailableAt map[string]time.Time `gorm:"-" json:"nextAvailableAt"`
+	// Loadout maps equipment slots to the accessory worn in them
+	Loadout map[string]string `gorm:"-" json:"loadout"`
+
+	// PendingEvents are written to the event log when the pet is next saved
+	PendingEvents []PetEvent `gorm:"-" json:"-"`
+}
+
+func (p *Pet) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// PetHealthTransition records a change in a pet's health state
+type PetHealthTransition struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PetID     uuid.UUID `gorm:"type:uuid;index" json:"petId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	From      string    `gorm:"not null" json:"from"`
+	To        string    `gorm:"not null" json:"to"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (t *PetHealthTransition) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// PetEvent is an entry in a pet's event log ("Ate a cookie")
+type PetEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PetID     uuid.UUID `gorm:"type:uuid;not null;index" json:"petId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Kind      string    `gorm:"not null" json:"kind"`
+	Message   string    `gorm:"not null" json:"message"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (e *PetEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// PetEquipment is an accessory a pet wears in one of its slots. Each
+// accessory the user owns can only be worn by one pet at a time.
+type PetEquipment struct {
+	PetID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"petId"`
+	Slot        string    `gorm:"primaryKey" json:"slot"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_pet_equipment_owner" json:"userId"`
+	AccessoryID string    `gorm:"not null;uniqueIndex:idx_pet_equipment_owner" json:"accessoryId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// PetQuestTally counts the quests of one category completed while the pet
+// was active; the mix decides how the pet evolves
+type PetQuestTally struct {
+	PetID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"petId"`
+	Category string    `gorm:"primaryKey" json:"category"`
+	Count    int       `gorm:"default:0" json:"count"`
+}
+
+// PetEvolution records a pet evolving into a new form
+type PetEvolution struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PetID     uuid.UUID `gorm:"type:uuid;not null;index" json:"petId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	FromForm  string    `json:"fromForm"`
+	ToForm    string    `gorm:"not null" json:"toForm"`
+	Level     int       `json:"level"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (e *PetEvolution) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// InventoryItem is a stack of shop items owned by a user
+type InventoryItem struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	ItemID    string    `gorm:"primaryKey" json:"itemId"`
+	Quantity  int       `gorm:"not null;default:0" json:"quantity"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// PetInteraction tracks a pet's use of one interaction (feed, play) for
+// cooldowns and diminishing returns
+type PetInteraction struct {
+	PetID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"petId"`
+	Action      string     `gorm:"primaryKey" json:"action"`
+	LastAt      *time.Time `json:"lastAt"`
+	WindowStart time.Time  `gorm:"not null" json:"windowStart"`
+	Count       int        `gorm:"not null;default:0" json:"count"`
+}
+
+// PetItemCooldown records when a pet may next use an item
+type PetItemCooldown struct {
+	PetID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"petId"`
+	ItemID      string    `gorm:"primaryKey" json:"itemId"`
+	AvailableAt time.Time `gorm:"not null" json:"availableAt"`
+}
+
 // Decoration represents purchased decorations
 type Decoration struct {
 	UserID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
@@ -62,6 +377,84 @@ type Decoration struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
+// LairLayout is a named arrangement of the user's decorations on the lair
+// grid. At most one layout per user is active; it is the one others see.
+type LairLayout struct {
+	ID         uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_lair_layouts_name;uniqueIndex:idx_lair_layouts_active,where:active" json:"userId"`
+	Name       string          `gorm:"not null;uniqueIndex:idx_lair_layouts_name" json:"name"`
+	Active     bool            `gorm:"default:false" json:"active"`
+	Placements []LairPlacement `gorm:"foreignKey:LayoutID;constraint:OnDelete:CASCADE" json:"placements"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+}
+
+func (l *LairLayout) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// LairPlacement puts an owned decoration on the grid: X, Y is its top-left
+// cell and Rotation (0, 90, 180 or 270) turns its footprint
+type LairPlacement struct {
+	LayoutID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Decoration string    `gorm:"primaryKey" json:"decoration"`
+	X          int       `gorm:"not null" json:"x"`
+	Y          int       `gorm:"not null" json:"y"`
+	Rotation   int       `gorm:"not null;default:0" json:"rotation"`
+}
+
+// TimeEntry represents a block of focused work logged against a task
+type TimeEntry struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_time_entries_active,where:ended_at IS NULL" json:"userId"`
+	TaskID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"taskId"`
+	Kind            string     `gorm:"not null;default:'timer'" json:"kind"`
+	PlannedMinutes  int        `gorm:"default:0" json:"plannedMinutes"`
+	StartedAt       time.Time  `gorm:"not null" json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationSeconds int        `gorm:"default:0" json:"durationSeconds"`
+	FocusCompleted  bool       `gorm:"default:false" json:"focusCompleted"`
+	BonusExp        int        `gorm:"default:0" json:"bonusExp"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+func (e *TimeEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// Vacation pauses a user's pet decay and health timers between StartsAt
+// and EndsAt. EndedAt is set when the user comes back early.
+type Vacation struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	StartsAt  time.Time  `gorm:"not null" json:"startsAt"`
+	EndsAt    time.Time  `gorm:"not null" json:"endsAt"`
+	EndedAt   *time.Time `json:"endedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func (v *Vacation) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// End is when the vacation actually ends
+func (v *Vacation) End() time.Time {
+	if v.EndedAt != nil {
+		return *v.EndedAt
+	}
+	return v.EndsAt
+}
+
 // DTO Models for API requests/responses
 
 type RegisterRequest struct {
@@ -82,28 +475,286 @@ type AuthResponse struct {
 
 type CreateTaskRequest struct {
 	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
+	Description string `json:"description" binding:"max=10000"`
+	Category    string `json:"category" binding:"max=30"`
 	Reward      int    `json:"reward" binding:"min=1"`
+	// ReviewerEmail makes the task pay out only after that user approves it
+	ReviewerEmail string `json:"reviewerEmail" binding:"omitempty,email"`
+}
+
+type ReviewTaskRequest struct {
+	Comment string `json:"comment" binding:"max=1000"`
 }
 
 type BulkTaskRequest struct {
 	Tasks []CreateTaskRequest `json:"tasks" binding:"required,min=1"`
 }
 
+type MoveTaskRequest struct {
+	StatusID uuid.UUID  `json:"statusId" binding:"required"`
+	BeforeID *uuid.UUID `json:"beforeId"`
+	AfterID  *uuid.UUID `json:"afterId"`
+}
+
+type CreateTaskStatusRequest struct {
+	Name       string `json:"name" binding:"required,max=50"`
+	Position   *int   `json:"position" binding:"omitempty,min=0"`
+	IsTerminal bool   `json:"isTerminal"`
+}
+
+type UpdateTaskStatusRequest struct {
+	Name       *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Position   *int    `json:"position" binding:"omitempty,min=0"`
+	IsTerminal *bool   `json:"isTerminal"`
+}
+
+type AttachmentResponse struct {
+	Attachment
+	DownloadURL  string `json:"downloadUrl"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+type StartTimerRequest struct {
+	Kind           string `json:"kind" binding:"omitempty,oneof=timer pomodoro"`
+	PlannedMinutes int    `json:"plannedMinutes" binding:"omitempty,min=1,max=180"`
+}
+
+type TaskTimeTotal struct {
+	TaskID        uuid.UUID `json:"taskId"`
+	TotalSeconds  int       `json:"totalSeconds"`
+	Sessions      int       `json:"sessions"`
+	FocusSessions int       `json:"focusSessions"`
+}
+
+type DailyTimeTotal struct {
+	Date          string `json:"date"`
+	TotalSeconds  int    `json:"totalSeconds"`
+	Sessions      int    `json:"sessions"`
+	FocusSessions int    `json:"focusSessions"`
+}
+
+type AdoptPetRequest struct {
+	Species string `json:"species" binding:"required"`
+	Name    string `json:"name" binding:"max=40"`
+	Method  string `json:"method" binding:"required,oneof=gold milestone"`
+}
+
+type RenamePetRequest struct {
+	Name string `json:"name" binding:"required,max=40"`
+}
+
+type RevivePetRequest struct {
+	Method string `json:"method" binding:"required,oneof=gold quests"`
+}
+
+type PetHealthResponse struct {
+	Health          string                `json:"health"`
+	HealthChangedAt *time.Time            `json:"healthChangedAt"`
+	StarvingSince   *time.Time            `json:"starvingSince"`
+	RevivalProgress int                   `json:"revivalProgress"`
+	ReviveGoldCost  int                   `json:"reviveGoldCost"`
+	ReviveQuests    int                   `json:"reviveQuests"`
+	Transitions     []PetHealthTransition `json:"transitions"`
+}
+
+type PetEvolutionResponse struct {
+	Form       string         `json:"form"`
+	FormName   string         `json:"formName"`
+	Tallies    map[string]int `json:"tallies"`
+	NextLevel  int            `json:"nextLevel,omitempty"`
+	NextForms  []string       `json:"nextForms,omitempty"`
+	Evolutions []PetEvolution `json:"evolutions"`
+}
+
+type EquipAccessoryRequest struct {
+	AccessoryID string `json:"accessoryId" binding:"required"`
+}
+
+type UnequipAccessoryRequest struct {
+	Slot string `json:"slot" binding:"required"`
+}
+
+type BuyItemRequest struct {
+	Quantity int `json:"quantity" binding:"omitempty,min=1,max=99"`
+}
+
+type UseItemRequest struct {
+	ItemID string `json:"itemId" binding:"required"`
+}
+
+type UseItemResponse struct {
+	Pet             *Pet       `json:"pet"`
+	Remaining       int        `json:"remaining"`
+	NextAvailableAt *time.Time `json:"nextAvailableAt"`
+}
+
+type StartVacationRequest struct {
+	// StartDate defaults to today; both dates are inclusive UTC days
+	StartDate string `json:"startDate" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"endDate" binding:"required,datetime=2006-01-02"`
+}
+
+type VacationResponse struct {
+	Current   *Vacation  `json:"current"`
+	Recent    []Vacation `json:"recent"`
+	Remaining int        `json:"remaining"`
+	MaxDays   int        `json:"maxDays"`
+}
+
 type BuyDecorationRequest struct {
 	Decoration string `json:"decoration" binding:"required"`
 }
 
+type AchievementResponse struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Progress    int            `json:"progress"`
+	Goal        int            `json:"goal"`
+	RewardGold  int            `json:"rewardGold,omitempty"`
+	RewardItems map[string]int `json:"rewardItems,omitempty"`
+	UnlockedAt  *time.Time     `json:"unlockedAt"`
+}
+
+// LootDrop is loot a completed quest dropped
+type LootDrop struct {
+	Tier     string `json:"tier"`
+	Item     string `json:"item,omitempty"`
+	Quantity int    `json:"quantity,omitempty"`
+	Gold     int    `json:"gold,omitempty"`
+	Rare     bool   `json:"rare"`
+	// Pity is set when the drop was guaranteed by the pity timer
+	Pity bool `json:"pity"`
+}
+
+// CompleteTaskResponse is the completed task with the loot it dropped
+type CompleteTaskResponse struct {
+	Task
+	Drops []LootDrop `json:"drops"`
+}
+
+type RecipeResponse struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Items       map[string]int `json:"items"`
+	Gold        int            `json:"gold"`
+	Item        string         `json:"item,omitempty"`
+	Decoration  string         `json:"decoration,omitempty"`
+	Quantity    int            `json:"quantity"`
+	MinLevel    int            `json:"minLevel,omitempty"`
+	Achievement string         `json:"achievement,omitempty"`
+	// Unlocked recipes can be crafted once the user has the inputs
+	Unlocked bool `json:"unlocked"`
+	CanCraft bool `json:"canCraft"`
+}
+
+type SendGiftRequest struct {
+	RecipientEmail string `json:"recipientEmail" binding:"required,email"`
+	Gold           int    `json:"gold" binding:"min=0"`
+	ItemID         string `json:"itemId"`
+	Quantity       int    `json:"quantity" binding:"min=0,max=99"`
+	Decoration     string `json:"decoration"`
+	Message        string `json:"message" binding:"max=280"`
+}
+
+type CraftRequest struct {
+	Recipe string `json:"recipe" binding:"required"`
+}
+
+type CraftResponse struct {
+	Recipe     string `json:"recipe"`
+	Item       string `json:"item,omitempty"`
+	Decoration string `json:"decoration,omitempty"`
+	Quantity   int    `json:"quantity"`
+	Balance    int    `json:"balance"`
+}
+
+// CharacterBonuses are what the character's attributes add, in percent:
+// strength to quest gold, intelligence to pet EXP from quests,
+// constitution to pet happiness from quests and perception to the chance
+// of loot
+type CharacterBonuses struct {
+	Gold         int `json:"gold"`
+	PetExp       int `json:"petExp"`
+	PetHappiness int `json:"petHappiness"`
+	Loot         int `json:"loot"`
+}
+
+type CharacterResponse struct {
+	Character
+	ExpToNextLevel int              `json:"expToNextLevel"`
+	Bonuses        CharacterBonuses `json:"bonuses"`
+}
+
+// MeResponse is the signed-in user with their streak and character
+type MeResponse struct {
+	User
+	Streak    *StreakResponse    `json:"streak"`
+	Character *CharacterResponse `json:"character"`
+}
+
+type TimeZoneRequest struct {
+	TimeZone string `json:"timeZone" binding:"required"`
+}
+
+type StreakMilestone struct {
+	Days int `json:"days"`
+	Gold int `json:"gold"`
+}
+
+type StreakResponse struct {
+	Current        int              `json:"current"`
+	Longest        int              `json:"longest"`
+	CompletedToday bool             `json:"completedToday"`
+	NextMilestone  *StreakMilestone `json:"nextMilestone"`
+	Freezes        int              `json:"freezes"`
+	MaxFreezes     int              `json:"maxFreezes"`
+	FreezePrice    int              `json:"freezePrice"`
+	CheckedInToday bool             `json:"checkedInToday"`
+	CheckInGold    int              `json:"checkInGold"`
+	TimeZone       string           `json:"timeZone"`
+}
+
+type CheckInResponse struct {
+	Gold    int             `json:"gold"`
+	Balance int             `json:"balance"`
+	Streak  *StreakResponse `json:"streak"`
+}
+
+type LairLayoutRequest struct {
+	Name       string                   `json:"name" binding:"required,max=50"`
+	Placements []PlaceDecorationRequest `json:"placements" binding:"max=200,dive"`
+}
+
+type PlaceDecorationRequest struct {
+	Decoration string `json:"decoration" binding:"required"`
+	X          int    `json:"x" binding:"min=0"`
+	Y          int    `json:"y" binding:"min=0"`
+	Rotation   int    `json:"rotation" binding:"oneof=0 90 180 270"`
+}
+
+type LairResponse struct {
+	Width   int          `json:"width"`
+	Height  int          `json:"height"`
+	Layouts []LairLayout `json:"layouts"`
+}
+
 type SyncRequest struct {
 	LastSyncAt time.Time `json:"lastSyncAt"`
 }
 
 type SyncResponse struct {
-	Tasks       []Task       `json:"tasks"`
-	Pet         *Pet         `json:"pet"`
-	Decorations []Decoration `json:"decorations"`
-	User        *User        `json:"user"`
-	SyncedAt    time.Time    `json:"syncedAt"`
+	Tasks       []Task             `json:"tasks"`
+	Statuses    []TaskStatus       `json:"statuses"`
+	Pet         *Pet               `json:"pet"`
+	Pets        []Pet              `json:"pets"`
+	PetEvents   []PetEvent         `json:"petEvents"`
+	Decorations []Decoration       `json:"decorations"`
+	Lair        *LairLayout        `json:"lair"`
+	User        *User              `json:"user"`
+	Character   *CharacterResponse `json:"character"`
+	SyncedAt    time.Time          `json:"syncedAt"`
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"guildquest/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB needs a scratch Postgres database; `docker-compose up -d
+// postgres` starts a server on localhost:5432
+func openTestDB(t *testing.T) *gorm.DB {
+	url := os.Getenv("DATABASE_TEST_URL")
+	if url == "" {
+		t.Skip("DATABASE_TEST_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.GoldTransaction{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, gold int) models.User {
+	user := models.User{Email: uuid.NewString() + "@example.com", PasswordHash: "x", Gold: gold}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func goldOf(t *testing.T, db *gorm.DB, userID uuid.UUID) int {
+	var user models.User
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	return user.Gold
+}
+
+func TestLedgerApplyMovesBalance(t *testing.T) {
+	db := openTestDB(t)
+	ledger := NewLedgerRepository(db)
+	user := createTestUser(t, db, 0)
+
+	credit := &models.GoldTransaction{UserID: user.ID, Amount: 100, Reason: models.GoldQuestReward}
+	debit := &models.GoldTransaction{UserID: user.ID, Amount: -30, Reason: models.GoldItemPurchase}
+	for _, entry := range []*models.GoldTransaction{credit, debit} {
+		if applied, err := ledger.Apply(entry); err != nil || !applied {
+			t.Fatalf("apply %d: applied %v, %v", entry.Amount, applied, err)
+		}
+	}
+
+	if credit.Balance != 100 || debit.Balance != 70 {
+		t.Fatalf("balances %d and %d, want 100 and 70", credit.Balance, debit.Balance)
+	}
+	if gold := goldOf(t, db, user.ID); gold != 70 {
+		t.Fatalf("user has %d gold, want 70", gold)
+	}
+}
+
+func TestLedgerRejectsOverdraft(t *testing.T) {
+	db := openTestDB(t)
+	ledger := NewLedgerRepository(db)
+	user := createTestUser(t, db, 50)
+
+	_, err := ledger.Apply(&models.GoldTransaction{UserID: user.ID, Amount: -80, Reason: models.GoldItemPurchase})
+	if !errors.Is(err, ErrInsufficientGold) {
+		t.Fatalf("overdraft returned %v, want ErrInsufficientGold", err)
+	}
+
+	var entries int64
+	db.Model(&models.GoldTransaction{}).Where("user_id = ?", user.ID).Count(&entries)
+	if gold := goldOf(t, db, user.ID); gold != 50 || entries != 0 {
+		t.Fatalf("refused debit left %d gold and %d entries, want 50 and 0", gold, entries)
+	}
+}
+
+func TestLedgerIdempotencyKey(t *testing.T) {
+	db := openTestDB(t)
+	ledger := NewLedgerRepository(db)
+	user := createTestUser(t, db, 100)
+	key := "item:kibble:retry"
+
+	first := &models.GoldTransaction{UserID: user.ID, Amount: -20, Reason: models.GoldItemPurchase, IdempotencyKey: &key}
+	if applied, err := ledger.Apply(first); err != nil || !applied {
+		t.Fatalf("first apply: applied %v, %v", applied, err)
+	}
+
+	retry := &models.GoldTransaction{UserID: user.ID, Amount: -20, Reason: models.GoldItemPurchase, IdempotencyKey: &key}
+	applied, err := ledger.Apply(retry)
+	if err != nil || applied {
+		t.Fatalf("retry: applied %v, %v; want a replay", applied, err)
+	}
+	if retry.ID != first.ID || retry.Balance != 80 {
+		t.Fatalf("replay filled in entry %s with balance %d, want %s and 80", retry.ID, retry.Balance, first.ID)
+	}
+	if gold := goldOf(t, db, user.ID); gold != 80 {
+		t.Fatalf("user has %d gold after a retry, want 80", gold)
+	}
+
+	// Keys are per user
+	other := createTestUser(t, db, 100)
+	if applied, err := ledger.Apply(&models.GoldTransaction{UserID: other.ID, Amount: -20, Reason: models.GoldItemPurchase, IdempotencyKey: &key}); err != nil || !applied {
+		t.Fatalf("another user's key: applied %v, %v", applied, err)
+	}
+}
+
+// Concurrent debits are serialised on the user row, so they can't
+// overspend and the balance stays the sum of the entries
+func TestLedgerConcurrentDebits(t *testing.T) {
+	db := openTestDB(t)
+	ledger := NewLedgerRepository(db)
+	user := createTestUser(t, db, 100)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ledger.Apply(&models.GoldTransaction{UserID: user.ID, Amount: -20, Reason: models.GoldItemPurchase})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrInsufficientGold) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var sum int
+	db.Model(&models.GoldTransaction{}).Where("user_id = ?", user.ID).Select("COALESCE(SUM(amount), 0)").Scan(&sum)
+	if gold := goldOf(t, db, user.ID); succeeded != 5 || gold != 0 || sum != -100 {
+		t.Fatalf("%d debits went through leaving %d gold and entries summing to %d, want 5, 0 and -100", succeeded, gold, sum)
+	}
+}
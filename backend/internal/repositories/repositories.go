@@ -2,20 +2,23 @@
 package repositories
 
 import (
+	"errors"
 	"time"
 
 	"guildquest/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uuid.UUID) (*models.User, error)
-	UpdateGold(userID uuid.UUID, gold int) error
 	GetGold(userID uuid.UUID) (int, error)
+	UpdateTimeZone(userID uuid.UUID, timeZone string) error
+	Lock(ids ...uuid.UUID) error
 }
 
 type userRepository struct {
@@ -48,16 +51,261 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) UpdateGold(userID uuid.UUID, gold int) error {
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("gold", gold).Error
-}
-
 func (r *userRepository) GetGold(userID uuid.UUID) (int, error) {
 	var user models.User
 	err := r.db.Select("gold").First(&user, "id = ?", userID).Error
 	return user.Gold, err
 }
 
+func (r *userRepository) UpdateTimeZone(userID uuid.UUID, timeZone string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).
+		UpdateColumn("time_zone", timeZone).Error
+}
+
+// Lock holds locks on the users' rows until the surrounding transaction
+// ends. Rows are locked in id order, so callers locking several users
+// can't deadlock one another.
+func (r *userRepository) Lock(ids ...uuid.UUID) error {
+	var users []models.User
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id IN ?", ids).Order("id").Find(&users).Error
+}
+
+// internal/repositories/ledger_repository.go
+
+// ErrInsufficientGold is returned when a debit would overdraw the balance
+var ErrInsufficientGold = errors.New("insufficient gold")
+
+type LedgerRepository interface {
+	Apply(entry *models.GoldTransaction) (bool, error)
+	FindByUserID(userID uuid.UUID, before time.Time, limit int) ([]models.GoldTransaction, error)
+}
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+// Apply appends the entry and moves the user's balance by its amount in
+// one transaction, holding a lock on the user row. An entry whose
+// idempotency key was already used is not applied again: Apply fills in
+// the earlier entry and reports false.
+func (r *ledgerRepository) Apply(entry *models.GoldTransaction) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "gold").First(&user, "id = ?", entry.UserID).Error; err != nil {
+			return err
+		}
+
+		// Checked under the lock, so a replay can't race the original
+		if entry.IdempotencyKey != nil {
+			err := tx.Where("user_id = ? AND idempotency_key = ?", entry.UserID, *entry.IdempotencyKey).
+				First(entry).Error
+			if err == nil {
+				return nil
+			}
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+		}
+
+		balance := user.Gold + entry.Amount
+		if entry.Amount < 0 && balance < 0 {
+			return ErrInsufficientGold
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", entry.UserID).
+			UpdateColumn("gold", balance).Error; err != nil {
+			return err
+		}
+
+		entry.Balance = balance
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// FindByUserID pages through the user's ledger, newest first, starting
+// before the given time
+func (r *ledgerRepository) FindByUserID(userID uuid.UUID, before time.Time, limit int) ([]models.GoldTransaction, error) {
+	var entries []models.GoldTransaction
+	err := r.db.Where("user_id = ? AND created_at < ?", userID, before).
+		Order("created_at DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// internal/repositories/achievement_repository.go
+
+type AchievementRepository interface {
+	FindByUserID(userID uuid.UUID) ([]models.UserAchievement, error)
+	Unlock(achievement *models.UserAchievement) (bool, error)
+}
+
+type achievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+func (r *achievementRepository) FindByUserID(userID uuid.UUID) ([]models.UserAchievement, error) {
+	var unlocked []models.UserAchievement
+	err := r.db.Where("user_id = ?", userID).Order("unlocked_at").Find(&unlocked).Error
+	return unlocked, err
+}
+
+// Unlock records the achievement, reporting false if the user already
+// had it
+func (r *achievementRepository) Unlock(achievement *models.UserAchievement) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(achievement)
+	return res.RowsAffected > 0, res.Error
+}
+
+// internal/repositories/character_repository.go
+
+type CharacterRepository interface {
+	FindByUserID(userID uuid.UUID) (*models.Character, error)
+	Lock(userID uuid.UUID) (*models.Character, error)
+	Save(character *models.Character) error
+}
+
+type characterRepository struct {
+	db *gorm.DB
+}
+
+func NewCharacterRepository(db *gorm.DB) CharacterRepository {
+	return &characterRepository{db: db}
+}
+
+// FindByUserID returns the user's character, or nil if they have none yet
+func (r *characterRepository) FindByUserID(userID uuid.UUID) (*models.Character, error) {
+	var character models.Character
+	err := r.db.First(&character, "user_id = ?", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &character, nil
+}
+
+// Lock loads the user's character, creating a level 1 one first if
+// needed, and holds a lock on its row until the surrounding transaction
+// ends
+func (r *characterRepository) Lock(userID uuid.UUID) (*models.Character, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Character{UserID: userID, Level: 1}).Error; err != nil {
+		return nil, err
+	}
+
+	var character models.Character
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&character, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &character, nil
+}
+
+func (r *characterRepository) Save(character *models.Character) error {
+	return r.db.Save(character).Error
+}
+
+// internal/repositories/streak_repository.go
+
+type StreakRepository interface {
+	FindByUserID(userID uuid.UUID) (*models.Streak, error)
+	Lock(userID uuid.UUID) (*models.Streak, error)
+	Save(streak *models.Streak) error
+}
+
+type streakRepository struct {
+	db *gorm.DB
+}
+
+func NewStreakRepository(db *gorm.DB) StreakRepository {
+	return &streakRepository{db: db}
+}
+
+// FindByUserID returns the user's streak, or nil if they have none yet
+func (r *streakRepository) FindByUserID(userID uuid.UUID) (*models.Streak, error) {
+	var streak models.Streak
+	err := r.db.First(&streak, "user_id = ?", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &streak, nil
+}
+
+// Lock loads the user's streak, creating an empty one first if needed, and
+// holds a lock on its row until the surrounding transaction ends
+func (r *streakRepository) Lock(userID uuid.UUID) (*models.Streak, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Streak{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var streak models.Streak
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&streak, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &streak, nil
+}
+
+func (r *streakRepository) Save(streak *models.Streak) error {
+	return r.db.Save(streak).Error
+}
+
+// internal/repositories/loot_repository.go
+
+type LootRepository interface {
+	Lock(userID uuid.UUID) (*models.LootPity, error)
+	Save(pity *models.LootPity) error
+}
+
+type lootRepository struct {
+	db *gorm.DB
+}
+
+func NewLootRepository(db *gorm.DB) LootRepository {
+	return &lootRepository{db: db}
+}
+
+// Lock loads the user's pity counter, creating it first if needed, and
+// holds a lock on its row until the surrounding transaction ends
+func (r *lootRepository) Lock(userID uuid.UUID) (*models.LootPity, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LootPity{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var pity models.LootPity
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&pity, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &pity, nil
+}
+
+func (r *lootRepository) Save(pity *models.LootPity) error {
+	return r.db.Save(pity).Error
+}
+
 // internal/repositories/task_repository.go
 
 type TaskRepository interface {
@@ -66,8 +314,16 @@ type TaskRepository interface {
 	FindByUserID(userID uuid.UUID) ([]models.Task, error)
 	FindByID(id uuid.UUID) (*models.Task, error)
 	MarkComplete(id uuid.UUID) error
+	Update(task *models.Task) error
 	Delete(id uuid.UUID) error
 	FindUpdatedSince(userID uuid.UUID, since time.Time) ([]models.Task, error)
+	FindByStatusID(statusID uuid.UUID) ([]models.Task, error)
+	FindPendingReview(reviewerID uuid.UUID) ([]models.Task, error)
+	CountCompleted(userID uuid.UUID) (int64, error)
+	FindCompletionTimes(userID uuid.UUID, since time.Time) ([]time.Time, error)
+	CountByStatusID(statusID uuid.UUID) (int64, error)
+	MaxRank(statusID uuid.UUID) (float64, error)
+	AssignMissingStatus(userID uuid.UUID, openStatusID, doneStatusID uuid.UUID) error
 }
 
 type taskRepository struct {
@@ -105,6 +361,10 @@ func (r *taskRepository) MarkComplete(id uuid.UUID) error {
 	return r.db.Model(&models.Task{}).Where("id = ?", id).Update("completed", true).Error
 }
 
+func (r *taskRepository) Update(task *models.Task) error {
+	return r.db.Save(task).Error
+}
+
 func (r *taskRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Task{}, "id = ?", id).Error
 }
@@ -115,14 +375,207 @@ func (r *taskRepository) FindUpdatedSince(userID uuid.UUID, since time.Time) ([]
 	return tasks, err
 }
 
+func (r *taskRepository) FindByStatusID(statusID uuid.UUID) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where("status_id = ?", statusID).Order("rank ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) FindPendingReview(reviewerID uuid.UUID) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where("reviewer_id = ? AND review_status = ?", reviewerID, models.ReviewPending).
+		Order("updated_at ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) CountCompleted(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).Where("user_id = ? AND completed", userID).Count(&count).Error
+	return count, err
+}
+
+// FindCompletionTimes returns when the user's completed tasks were
+// completed, for those completed since the given time
+func (r *taskRepository) FindCompletionTimes(userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.Model(&models.Task{}).
+		Where("user_id = ? AND completed AND completed_at >= ?", userID, since).
+		Order("completed_at DESC").
+		Pluck("completed_at", &times).Error
+	return times, err
+}
+
+func (r *taskRepository) CountByStatusID(statusID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).Where("status_id = ?", statusID).Count(&count).Error
+	return count, err
+}
+
+func (r *taskRepository) MaxRank(statusID uuid.UUID) (float64, error) {
+	var rank float64
+	err := r.db.Model(&models.Task{}).
+		Select("COALESCE(MAX(rank), 0)").
+		Where("status_id = ?", statusID).
+		Scan(&rank).Error
+	return rank, err
+}
+
+// AssignMissingStatus places tasks created before board columns existed
+func (r *taskRepository) AssignMissingStatus(userID uuid.UUID, openStatusID, doneStatusID uuid.UUID) error {
+	return r.db.Model(&models.Task{}).
+		Where("user_id = ? AND status_id IS NULL", userID).
+		Update("status_id", gorm.Expr("CASE WHEN completed THEN ?::uuid ELSE ?::uuid END", doneStatusID, openStatusID)).Error
+}
+
+// internal/repositories/attachment_repository.go
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	FindByID(id uuid.UUID) (*models.Attachment, error)
+	FindByTaskID(taskID uuid.UUID) ([]models.Attachment, error)
+	Delete(id uuid.UUID) error
+	DeleteByTaskID(taskID uuid.UUID) ([]models.Attachment, error)
+	FindOrphaned(limit int) ([]models.Attachment, error)
+	SumSizeByUserID(userID uuid.UUID) (int64, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(id uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.First(&attachment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) FindByTaskID(taskID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("task_id = ?", taskID).Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Attachment{}, "id = ?", id).Error
+}
+
+// DeleteByTaskID removes the task's attachments, returning them so their
+// blobs can be deleted too
+func (r *attachmentRepository) DeleteByTaskID(taskID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Clauses(clause.Returning{}).Where("task_id = ?", taskID).Delete(&attachments).Error
+	return attachments, err
+}
+
+// FindOrphaned returns attachments left behind by tasks deleted before
+// their attachments went with them
+func (r *attachmentRepository) FindOrphaned(limit int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.id = attachments.task_id)").
+		Limit(limit).Find(&attachments).Error
+	return attachments, err
+}
+
+// SumSizeByUserID counts storage used by all of the user's attachments
+func (r *attachmentRepository) SumSizeByUserID(userID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Attachment{}).
+		Select("COALESCE(SUM(size), 0)").
+		Where("user_id = ?", userID).
+		Scan(&total).Error
+	return total, err
+}
+
+// internal/repositories/task_status_repository.go
+
+type TaskStatusRepository interface {
+	CreateBulk(statuses []models.TaskStatus) error
+	Create(status *models.TaskStatus) error
+	FindByUserID(userID uuid.UUID) ([]models.TaskStatus, error)
+	FindByID(id uuid.UUID) (*models.TaskStatus, error)
+	Update(status *models.TaskStatus) error
+	Delete(id uuid.UUID) error
+}
+
+type taskStatusRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskStatusRepository(db *gorm.DB) TaskStatusRepository {
+	return &taskStatusRepository{db: db}
+}
+
+func (r *taskStatusRepository) CreateBulk(statuses []models.TaskStatus) error {
+	return r.db.Create(&statuses).Error
+}
+
+func (r *taskStatusRepository) Create(status *models.TaskStatus) error {
+	return r.db.Create(status).Error
+}
+
+func (r *taskStatusRepository) FindByUserID(userID uuid.UUID) ([]models.TaskStatus, error) {
+	var statuses []models.TaskStatus
+	err := r.db.Where("user_id = ?", userID).Order("position ASC, created_at ASC").Find(&statuses).Error
+	return statuses, err
+}
+
+func (r *taskStatusRepository) FindByID(id uuid.UUID) (*models.TaskStatus, error) {
+	var status models.TaskStatus
+	err := r.db.First(&status, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *taskStatusRepository) Update(status *models.TaskStatus) error {
+	return r.db.Save(status).Error
+}
+
+func (r *taskStatusRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.TaskStatus{}, "id = ?", id).Error
+}
+
 // internal/repositories/pet_repository.go
 
 type PetRepository interface {
 	Create(pet *models.Pet) error
 	FindByUserID(userID uuid.UUID) (*models.Pet, error)
+	FindByID(id uuid.UUID) (*models.Pet, error)
+	FindAllByUserID(userID uuid.UUID) ([]models.Pet, error)
+	MaxLevelByUserID(userID uuid.UUID) (int, error)
+	SetActive(userID uuid.UUID, petID uuid.UUID) error
 	Update(pet *models.Pet) error
 	AddExp(userID uuid.UUID, exp int) error
 	UpdateStats(userID uuid.UUID, hunger, happiness int) error
+	SaveDecay(pet *models.Pet, prevDecayedAt *time.Time) (bool, error)
+	FindInBatches(batchSize int, fn func(pets []models.Pet) error) error
+	AddHealthTransitions(transitions []models.PetHealthTransition) error
+	FindHealthTransitions(petID uuid.UUID, limit int) ([]models.PetHealthTransition, error)
+	IncrementQuestTally(petID uuid.UUID, category string) error
+	FindQuestTallies(petID uuid.UUID) (map[string]int, error)
+	AddEvolution(evolution *models.PetEvolution) error
+	FindEvolutions(petID uuid.UUID) ([]models.PetEvolution, error)
+	FindInteractions(petID uuid.UUID) ([]models.PetInteraction, error)
+	UpdateInteraction(petID uuid.UUID, action string, fn func(interaction *models.PetInteraction) error) error
+	UpdateMood(petID uuid.UUID, from, to string) (bool, error)
+	AddEvents(events []models.PetEvent) error
+	FindEvents(petID uuid.UUID, limit int) ([]models.PetEvent, error)
+	FindEventsSince(userID uuid.UUID, since time.Time, limit int) ([]models.PetEvent, error)
+	FindEquipment(petID uuid.UUID) ([]models.PetEquipment, error)
+	Equip(equipment *models.PetEquipment) error
+	Unequip(petID uuid.UUID, slot string) (bool, error)
 }
 
 type petRepository struct {
@@ -137,39 +590,394 @@ func (r *petRepository) Create(pet *models.Pet) error {
 	return r.db.Create(pet).Error
 }
 
+// FindByUserID returns the user's active pet
 func (r *petRepository) FindByUserID(userID uuid.UUID) (*models.Pet, error) {
 	var pet models.Pet
-	err := r.db.First(&pet, "user_id = ?", userID).Error
+	err := r.db.First(&pet, "user_id = ? AND active", userID).Error
 	if err != nil {
 		return nil, err
 	}
 	return &pet, nil
 }
 
+func (r *petRepository) FindByID(id uuid.UUID) (*models.Pet, error) {
+	var pet models.Pet
+	err := r.db.First(&pet, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pet, nil
+}
+
+func (r *petRepository) FindAllByUserID(userID uuid.UUID) ([]models.Pet, error) {
+	var pets []models.Pet
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&pets).Error
+	return pets, err
+}
+
+func (r *petRepository) MaxLevelByUserID(userID uuid.UUID) (int, error) {
+	var level int
+	err := r.db.Model(&models.Pet{}).
+		Select("COALESCE(MAX(level), 0)").
+		Where("user_id = ?", userID).
+		Scan(&level).Error
+	return level, err
+}
+
+// SetActive makes petID the user's only active pet
+func (r *petRepository) SetActive(userID uuid.UUID, petID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Pet{}).
+			Where("user_id = ? AND active", userID).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.Pet{}).
+			Where("id = ? AND user_id = ?", petID, userID).
+			Update("active", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
 func (r *petRepository) Update(pet *models.Pet) error {
 	return r.db.Save(pet).Error
 }
 
 func (r *petRepository) AddExp(userID uuid.UUID, exp int) error {
-	return r.db.Model(&models.Pet{}).Where("user_id = ?", userID).
+	return r.db.Model(&models.Pet{}).Where("user_id = ? AND active", userID).
 		Update("exp", gorm.Expr("exp + ?", exp)).Error
 }
 
 func (r *petRepository) UpdateStats(userID uuid.UUID, hunger, happiness int) error {
-	return r.db.Model(&models.Pet{}).Where("user_id = ?", userID).
+	return r.db.Model(&models.Pet{}).Where("user_id = ? AND active", userID).
 		Updates(map[string]interface{}{
 			"hunger":    hunger,
 			"happiness": happiness,
 		}).Error
 }
 
+// SaveDecay writes decayed stats unless another writer has already
+// advanced the pet's decay clock past prevDecayedAt.
+func (r *petRepository) SaveDecay(pet *models.Pet, prevDecayedAt *time.Time) (bool, error) {
+	q := r.db.Model(&models.Pet{}).Where("id = ?", pet.ID)
+	if prevDecayedAt == nil {
+		q = q.Where("decayed_at IS NULL")
+	} else {
+		q = q.Where("decayed_at = ?", *prevDecayedAt)
+	}
+
+	res := q.Updates(map[string]interface{}{
+		"hunger":            pet.Hunger,
+		"happiness":         pet.Happiness,
+		"level":             pet.Level,
+		"exp":               pet.Exp,
+		"health":            pet.Health,
+		"health_changed_at": pet.HealthChangedAt,
+		"starving_since":    pet.StarvingSince,
+		"revival_progress":  pet.RevivalProgress,
+		"decayed_at":        pet.DecayedAt,
+		"hunger_carry":      pet.HungerCarry,
+		"happiness_carry":   pet.HappinessCarry,
+	})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *petRepository) AddHealthTransitions(transitions []models.PetHealthTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	return r.db.Create(&transitions).Error
+}
+
+func (r *petRepository) FindHealthTransitions(petID uuid.UUID, limit int) ([]models.PetHealthTransition, error) {
+	var transitions []models.PetHealthTransition
+	err := r.db.Where("pet_id = ?", petID).Order("created_at DESC").Limit(limit).Find(&transitions).Error
+	return transitions, err
+}
+
+func (r *petRepository) IncrementQuestTally(petID uuid.UUID, category string) error {
+	tally := models.PetQuestTally{PetID: petID, Category: category, Count: 1}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pet_id"}, {Name: "category"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("pet_quest_tallies.count + 1")}),
+	}).Create(&tally).Error
+}
+
+func (r *petRepository) FindQuestTallies(petID uuid.UUID) (map[string]int, error) {
+	var rows []models.PetQuestTally
+	if err := r.db.Where("pet_id = ?", petID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tallies := make(map[string]int, len(rows))
+	for _, row := range rows {
+		tallies[row.Category] = row.Count
+	}
+	return tallies, nil
+}
+
+func (r *petRepository) AddEvolution(evolution *models.PetEvolution) error {
+	return r.db.Create(evolution).Error
+}
+
+func (r *petRepository) FindEvolutions(petID uuid.UUID) ([]models.PetEvolution, error) {
+	var evolutions []models.PetEvolution
+	err := r.db.Where("pet_id = ?", petID).Order("created_at").Find(&evolutions).Error
+	return evolutions, err
+}
+
+func (r *petRepository) FindInteractions(petID uuid.UUID) ([]models.PetInteraction, error) {
+	var interactions []models.PetInteraction
+	err := r.db.Where("pet_id = ?", petID).Find(&interactions).Error
+	return interactions, err
+}
+
+// UpdateInteraction runs fn on the pet's interaction row while holding a
+// row lock, saving the row if fn succeeds. Concurrent interactions with the
+// same pet are serialized.
+func (r *petRepository) UpdateInteraction(petID uuid.UUID, action string, fn func(interaction *models.PetInteraction) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		interaction := models.PetInteraction{PetID: petID, Action: action, WindowStart: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&interaction).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("pet_id = ? AND action = ?", petID, action).
+			First(&interaction).Error; err != nil {
+			return err
+		}
+
+		if err := fn(&interaction); err != nil {
+			return err
+		}
+
+		return tx.Save(&interaction).Error
+	})
+}
+
+// UpdateMood changes the stored mood, reporting false if another request
+// already moved it off from
+func (r *petRepository) UpdateMood(petID uuid.UUID, from, to string) (bool, error) {
+	res := r.db.Model(&models.Pet{}).Where("id = ? AND mood = ?", petID, from).UpdateColumn("mood", to)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *petRepository) AddEvents(events []models.PetEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Create(&events).Error
+}
+
+func (r *petRepository) FindEvents(petID uuid.UUID, limit int) ([]models.PetEvent, error) {
+	var events []models.PetEvent
+	err := r.db.Where("pet_id = ?", petID).Order("created_at DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *petRepository) FindEventsSince(userID uuid.UUID, since time.Time, limit int) ([]models.PetEvent, error) {
+	var events []models.PetEvent
+	err := r.db.Where("user_id = ? AND created_at > ?", userID, since).
+		Order("created_at DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *petRepository) FindEquipment(petID uuid.UUID) ([]models.PetEquipment, error) {
+	var equipment []models.PetEquipment
+	err := r.db.Where("pet_id = ?", petID).Order("slot").Find(&equipment).Error
+	return equipment, err
+}
+
+// Equip puts the accessory in the pet's slot, replacing what was there and
+// taking the accessory off any other pet wearing it
+func (r *petRepository) Equip(equipment *models.PetEquipment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND accessory_id = ?", equipment.UserID, equipment.AccessoryID).
+			Delete(&models.PetEquipment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "pet_id"}, {Name: "slot"}},
+			DoUpdates: clause.AssignmentColumns([]string{"accessory_id", "created_at"}),
+		}).Create(equipment).Error
+	})
+}
+
+func (r *petRepository) Unequip(petID uuid.UUID, slot string) (bool, error) {
+	res := r.db.Where("pet_id = ? AND slot = ?", petID, slot).Delete(&models.PetEquipment{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *petRepository) FindInBatches(batchSize int, fn func(pets []models.Pet) error) error {
+	var pets []models.Pet
+	return r.db.FindInBatches(&pets, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(pets)
+	}).Error
+}
+
+// internal/repositories/inventory_repository.go
+
+type InventoryRepository interface {
+	FindByUserID(userID uuid.UUID) ([]models.InventoryItem, error)
+	Quantity(userID uuid.UUID, itemID string) (int, error)
+	Add(userID uuid.UUID, itemID string, quantity int) error
+	Consume(userID uuid.UUID, itemID string) (bool, error)
+	Remove(userID uuid.UUID, itemID string, quantity int) (bool, error)
+	FindCooldown(petID uuid.UUID, itemID string) (*time.Time, error)
+	SetCooldown(petID uuid.UUID, itemID string, availableAt time.Time) error
+}
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) FindByUserID(userID uuid.UUID) ([]models.InventoryItem, error) {
+	var items []models.InventoryItem
+	err := r.db.Where("user_id = ? AND quantity > 0", userID).Order("item_id").Find(&items).Error
+	return items, err
+}
+
+func (r *inventoryRepository) Quantity(userID uuid.UUID, itemID string) (int, error) {
+	var quantity int
+	err := r.db.Model(&models.InventoryItem{}).
+		Where("user_id = ? AND item_id = ?", userID, itemID).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&quantity).Error
+	return quantity, err
+}
+
+func (r *inventoryRepository) Add(userID uuid.UUID, itemID string, quantity int) error {
+	item := models.InventoryItem{UserID: userID, ItemID: itemID, Quantity: quantity}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   gorm.Expr("inventory_items.quantity + ?", quantity),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&item).Error
+}
+
+// Consume takes one of the item, reporting false if the user has none left
+func (r *inventoryRepository) Consume(userID uuid.UUID, itemID string) (bool, error) {
+	return r.Remove(userID, itemID, 1)
+}
+
+// Remove takes quantity of the item, reporting false (and taking none) if
+// the user has fewer
+func (r *inventoryRepository) Remove(userID uuid.UUID, itemID string, quantity int) (bool, error) {
+	res := r.db.Model(&models.InventoryItem{}).
+		Where("user_id = ? AND item_id = ? AND quantity >= ?", userID, itemID, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *inventoryRepository) FindCooldown(petID uuid.UUID, itemID string) (*time.Time, error) {
+	var cooldown models.PetItemCooldown
+	err := r.db.Where("pet_id = ? AND item_id = ?", petID, itemID).First(&cooldown).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cooldown.AvailableAt, nil
+}
+
+func (r *inventoryRepository) SetCooldown(petID uuid.UUID, itemID string, availableAt time.Time) error {
+	cooldown := models.PetItemCooldown{PetID: petID, ItemID: itemID, AvailableAt: availableAt}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pet_id"}, {Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"available_at"}),
+	}).Create(&cooldown).Error
+}
+
+// internal/repositories/vacation_repository.go
+
+type VacationRepository interface {
+	Create(vacation *models.Vacation) error
+	Update(vacation *models.Vacation) error
+	Delete(id uuid.UUID) error
+	FindOverlapping(userID uuid.UUID, from, to time.Time) ([]models.Vacation, error)
+	FindScheduled(userID uuid.UUID, now time.Time) (*models.Vacation, error)
+	FindRecent(userID uuid.UUID, limit int) ([]models.Vacation, error)
+	CountStartedSince(userID uuid.UUID, since time.Time) (int64, error)
+}
+
+type vacationRepository struct {
+	db *gorm.DB
+}
+
+func NewVacationRepository(db *gorm.DB) VacationRepository {
+	return &vacationRepository{db: db}
+}
+
+func (r *vacationRepository) Create(vacation *models.Vacation) error {
+	return r.db.Create(vacation).Error
+}
+
+func (r *vacationRepository) Update(vacation *models.Vacation) error {
+	return r.db.Save(vacation).Error
+}
+
+func (r *vacationRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Vacation{}, "id = ?", id).Error
+}
+
+// FindOverlapping returns the user's vacations that overlap [from, to),
+// oldest first
+func (r *vacationRepository) FindOverlapping(userID uuid.UUID, from, to time.Time) ([]models.Vacation, error) {
+	var vacations []models.Vacation
+	err := r.db.Where("user_id = ? AND starts_at < ? AND COALESCE(ended_at, ends_at) > ?", userID, to, from).
+		Order("starts_at").Find(&vacations).Error
+	return vacations, err
+}
+
+// FindScheduled returns the user's current or upcoming vacation, or nil
+func (r *vacationRepository) FindScheduled(userID uuid.UUID, now time.Time) (*models.Vacation, error) {
+	var vacation models.Vacation
+	err := r.db.Where("user_id = ? AND COALESCE(ended_at, ends_at) > ?", userID, now).
+		Order("starts_at").First(&vacation).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &vacation, nil
+}
+
+func (r *vacationRepository) FindRecent(userID uuid.UUID, limit int) ([]models.Vacation, error) {
+	var vacations []models.Vacation
+	err := r.db.Where("user_id = ?", userID).Order("starts_at DESC").Limit(limit).Find(&vacations).Error
+	return vacations, err
+}
+
+func (r *vacationRepository) CountStartedSince(userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Vacation{}).Where("user_id = ? AND starts_at >= ?", userID, since).Count(&count).Error
+	return count, err
+}
+
 // internal/repositories/decoration_repository.go
 
 type DecorationRepository interface {
 	Create(decoration *models.Decoration) error
 	FindByUserID(userID uuid.UUID) ([]models.Decoration, error)
 	Exists(userID uuid.UUID, decoration string) (bool, error)
+	CountByUserID(userID uuid.UUID) (int64, error)
 	FindUpdatedSince(userID uuid.UUID, since time.Time) ([]models.Decoration, error)
+	Delete(userID uuid.UUID, decoration string) (bool, error)
 }
 
 type decorationRepository struct {
@@ -198,8 +1006,378 @@ func (r *decorationRepository) Exists(userID uuid.UUID, decoration string) (bool
 	return count > 0, err
 }
 
+func (r *decorationRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Decoration{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
 func (r *decorationRepository) FindUpdatedSince(userID uuid.UUID, since time.Time) ([]models.Decoration, error) {
 	var decorations []models.Decoration
 	err := r.db.Where("user_id = ? AND created_at > ?", userID, since).Find(&decorations).Error
 	return decorations, err
 }
+
+// Delete takes the decoration from the user, reporting false if they
+// didn't own it
+func (r *decorationRepository) Delete(userID uuid.UUID, decoration string) (bool, error) {
+	res := r.db.Where("user_id = ? AND decoration = ?", userID, decoration).Delete(&models.Decoration{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// internal/repositories/lair_repository.go
+
+type LairRepository interface {
+	Create(layout *models.LairLayout) error
+	Save(layout *models.LairLayout) error
+	Delete(id uuid.UUID) error
+	FindByID(id uuid.UUID) (*models.LairLayout, error)
+	FindByUserID(userID uuid.UUID) ([]models.LairLayout, error)
+	FindActive(userID uuid.UUID) (*models.LairLayout, error)
+	SetActive(userID uuid.UUID, layoutID uuid.UUID) error
+	Unplace(userID uuid.UUID, decoration string) error
+}
+
+type lairRepository struct {
+	db *gorm.DB
+}
+
+func NewLairRepository(db *gorm.DB) LairRepository {
+	return &lairRepository{db: db}
+}
+
+func (r *lairRepository) Create(layout *models.LairLayout) error {
+	return r.db.Create(layout).Error
+}
+
+// Save writes the layout's name and replaces its placements
+func (r *lairRepository) Save(layout *models.LairLayout) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(layout).Update("name", layout.Name).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("layout_id = ?", layout.ID).Delete(&models.LairPlacement{}).Error; err != nil {
+			return err
+		}
+		if len(layout.Placements) == 0 {
+			return nil
+		}
+		for i := range layout.Placements {
+			layout.Placements[i].LayoutID = layout.ID
+		}
+		return tx.Create(&layout.Placements).Error
+	})
+}
+
+func (r *lairRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.LairLayout{}, "id = ?", id).Error
+}
+
+func (r *lairRepository) FindByID(id uuid.UUID) (*models.LairLayout, error) {
+	var layout models.LairLayout
+	err := r.db.Preload("Placements").First(&layout, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+func (r *lairRepository) FindByUserID(userID uuid.UUID) ([]models.LairLayout, error) {
+	var layouts []models.LairLayout
+	err := r.db.Preload("Placements").Where("user_id = ?", userID).
+		Order("created_at").Find(&layouts).Error
+	return layouts, err
+}
+
+func (r *lairRepository) FindActive(userID uuid.UUID) (*models.LairLayout, error) {
+	var layout models.LairLayout
+	err := r.db.Preload("Placements").Where("user_id = ? AND active", userID).First(&layout).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+func (r *lairRepository) SetActive(userID uuid.UUID, layoutID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.LairLayout{}).
+			Where("user_id = ? AND active", userID).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.LairLayout{}).
+			Where("id = ? AND user_id = ?", layoutID, userID).
+			Update("active", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Unplace takes the decoration out of all of the user's layouts
+func (r *lairRepository) Unplace(userID uuid.UUID, decoration string) error {
+	return r.db.Where("decoration = ? AND layout_id IN (?)", decoration,
+		r.db.Model(&models.LairLayout{}).Select("id").Where("user_id = ?", userID)).
+		Delete(&models.LairPlacement{}).Error
+}
+
+// internal/repositories/gift_repository.go
+
+type GiftRepository interface {
+	Create(gift *models.Gift) error
+	FindByID(id uuid.UUID) (*models.Gift, error)
+	FindByIdempotencyKey(senderID uuid.UUID, key string) (*models.Gift, error)
+	FindByRecipient(recipientID uuid.UUID, status string) ([]models.Gift, error)
+	FindBySender(senderID uuid.UUID, limit int) ([]models.Gift, error)
+	Respond(gift *models.Gift, status string, at time.Time) (bool, error)
+	SentSince(senderID uuid.UUID, since time.Time) (int64, int, error)
+	GoldReceivedSince(recipientID uuid.UUID, since time.Time) (int, error)
+}
+
+type giftRepository struct {
+	db *gorm.DB
+}
+
+func NewGiftRepository(db *gorm.DB) GiftRepository {
+	return &giftRepository{db: db}
+}
+
+func (r *giftRepository) Create(gift *models.Gift) error {
+	return r.db.Create(gift).Error
+}
+
+func (r *giftRepository) FindByID(id uuid.UUID) (*models.Gift, error) {
+	var gift models.Gift
+	err := r.db.First(&gift, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gift, nil
+}
+
+func (r *giftRepository) FindByIdempotencyKey(senderID uuid.UUID, key string) (*models.Gift, error) {
+	var gift models.Gift
+	err := r.db.First(&gift, "sender_id = ? AND idempotency_key = ?", senderID, key).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gift, nil
+}
+
+// FindByRecipient lists the gifts sent to the user with the given status,
+// newest first
+func (r *giftRepository) FindByRecipient(recipientID uuid.UUID, status string) ([]models.Gift, error) {
+	var gifts []models.Gift
+	err := r.db.Where("recipient_id = ? AND status = ?", recipientID, status).
+		Order("created_at DESC").Find(&gifts).Error
+	return gifts, err
+}
+
+func (r *giftRepository) FindBySender(senderID uuid.UUID, limit int) ([]models.Gift, error) {
+	var gifts []models.Gift
+	err := r.db.Where("sender_id = ?", senderID).
+		Order("created_at DESC").Limit(limit).Find(&gifts).Error
+	return gifts, err
+}
+
+// Respond moves a pending gift to status, reporting false if it was no
+// longer pending (so only one response wins)
+func (r *giftRepository) Respond(gift *models.Gift, status string, at time.Time) (bool, error) {
+	res := r.db.Model(&models.Gift{}).
+		Where("id = ? AND status = ?", gift.ID, models.GiftPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": at})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	gift.Status, gift.RespondedAt = status, &at
+	return true, nil
+}
+
+// SentSince counts the gifts the user has sent since the given time and
+// the gold in them
+func (r *giftRepository) SentSince(senderID uuid.UUID, since time.Time) (int64, int, error) {
+	var totals struct {
+		Count int64
+		Gold  int
+	}
+	err := r.db.Model(&models.Gift{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(gold), 0) AS gold").
+		Where("sender_id = ? AND created_at >= ?", senderID, since).
+		Scan(&totals).Error
+	return totals.Count, totals.Gold, err
+}
+
+// GoldReceivedSince sums the gold in gifts sent to the user since the
+// given time, except those they declined
+func (r *giftRepository) GoldReceivedSince(recipientID uuid.UUID, since time.Time) (int, error) {
+	var gold int
+	err := r.db.Model(&models.Gift{}).
+		Where("recipient_id = ? AND created_at >= ? AND status <> ?", recipientID, since, models.GiftDeclined).
+		Select("COALESCE(SUM(gold), 0)").Scan(&gold).Error
+	return gold, err
+}
+
+// internal/repositories/time_entry_repository.go
+
+type TimeEntryRepository interface {
+	Create(entry *models.TimeEntry) error
+	Close(entry *models.TimeEntry) (bool, error)
+	StopByTaskID(taskID uuid.UUID, at time.Time) error
+	FindActiveByUserID(userID uuid.UUID) (*models.TimeEntry, error)
+	SumByTask(taskID uuid.UUID) (*models.TaskTimeTotal, error)
+	SumByDay(userID uuid.UUID, from, to time.Time) ([]models.DailyTimeTotal, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// Close ends the entry only if it is still running, reporting false when a
+// concurrent stop got there first
+func (r *timeEntryRepository) Close(entry *models.TimeEntry) (bool, error) {
+	res := r.db.Model(&models.TimeEntry{}).
+		Where("id = ? AND ended_at IS NULL", entry.ID).
+		Updates(map[string]interface{}{
+			"ended_at":         entry.EndedAt,
+			"duration_seconds": entry.DurationSeconds,
+			"focus_completed":  entry.FocusCompleted,
+			"bonus_exp":        entry.BonusExp,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// StopByTaskID ends any timer still running on the task, without a focus bonus
+func (r *timeEntryRepository) StopByTaskID(taskID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.TimeEntry{}).
+		Where("task_id = ? AND ended_at IS NULL", taskID).
+		Updates(map[string]interface{}{
+			"ended_at":         at,
+			"duration_seconds": gorm.Expr("GREATEST(EXTRACT(EPOCH FROM (? - started_at)), 0)::int", at),
+		}).Error
+}
+
+func (r *timeEntryRepository) FindActiveByUserID(userID uuid.UUID) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.First(&entry, "user_id = ? AND ended_at IS NULL", userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) SumByTask(taskID uuid.UUID) (*models.TaskTimeTotal, error) {
+	var total models.TaskTimeTotal
+	err := r.db.Model(&models.TimeEntry{}).
+		Select("COALESCE(SUM(duration_seconds), 0) AS total_seconds, COUNT(*) AS sessions, "+
+			"COUNT(*) FILTER (WHERE focus_completed) AS focus_sessions").
+		Where("task_id = ? AND ended_at IS NOT NULL", taskID).
+		Scan(&total).Error
+	total.TaskID = taskID
+	return &total, err
+}
+
+func (r *timeEntryRepository) SumByDay(userID uuid.UUID, from, to time.Time) ([]models.DailyTimeTotal, error) {
+	var totals []models.DailyTimeTotal
+	err := r.db.Model(&models.TimeEntry{}).
+		Select("to_char(started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date, "+
+			"SUM(duration_seconds) AS total_seconds, COUNT(*) AS sessions, "+
+			"COUNT(*) FILTER (WHERE focus_completed) AS focus_sessions").
+		Where("user_id = ? AND ended_at IS NOT NULL AND started_at >= ? AND started_at < ?", userID, from, to).
+		Group("date").
+		Order("date").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// internal/repositories/unit_of_work.go
+
+// Repositories is the set of repositories a multi-step operation works
+// with, all sharing one database handle
+type Repositories struct {
+	Users        UserRepository
+	Ledger       LedgerRepository
+	Achievements AchievementRepository
+	Characters   CharacterRepository
+	Streaks      StreakRepository
+	Loot         LootRepository
+	Tasks        TaskRepository
+	Statuses     TaskStatusRepository
+	Attachments  AttachmentRepository
+	Pets         PetRepository
+	Inventory    InventoryRepository
+	Vacations    VacationRepository
+	Decorations  DecorationRepository
+	Lairs        LairRepository
+	Gifts        GiftRepository
+	TimeEntries  TimeEntryRepository
+
+	// Work runs nested units of work on the same handle
+	Work UnitOfWork
+}
+
+// NewRepositories builds every repository on db, which may be a
+// transaction handle
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:        NewUserRepository(db),
+		Ledger:       NewLedgerRepository(db),
+		Achievements: NewAchievementRepository(db),
+		Characters:   NewCharacterRepository(db),
+		Streaks:      NewStreakRepository(db),
+		Loot:         NewLootRepository(db),
+		Tasks:        NewTaskRepository(db),
+		Statuses:     NewTaskStatusRepository(db),
+		Attachments:  NewAttachmentRepository(db),
+		Pets:         NewPetRepository(db),
+		Inventory:    NewInventoryRepository(db),
+		Vacations:    NewVacationRepository(db),
+		Decorations:  NewDecorationRepository(db),
+		Lairs:        NewLairRepository(db),
+		Gifts:        NewGiftRepository(db),
+		TimeEntries:  NewTimeEntryRepository(db),
+		Work:         NewUnitOfWork(db),
+	}
+}
+
+// UnitOfWork runs several repository calls atomically
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committing if it returns nil and rolling
+	// back otherwise. Units of work (and repositories that open their own
+	// transaction) nested inside it become savepoints.
+	Do(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
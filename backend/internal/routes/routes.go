@@ -2,10 +2,12 @@
 package routes
 
 import (
+	"guildquest/internal/config"
 	"guildquest/internal/handlers"
 	"guildquest/internal/middleware"
 	"guildquest/internal/repositories"
 	"guildquest/internal/services"
+	"guildquest/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -87,27 +89,67 @@ func SetupRoutes(
 func SetupRoutesWithAuth(
 	router *gin.RouterGroup,
 	db *gorm.DB,
-	jwtSecret string,
+	store storage.BlobStore,
+	cfg *config.Config,
 ) {
+	jwtSecret := cfg.JWTSecret
+	petCfg := services.NewPetConfig(cfg)
+	streakCfg := services.NewStreakConfig(cfg)
+	lootRNG := services.NewLootRNG(cfg.LootSeed)
+
 	// Initialize all layers
 	userRepo := repositories.NewUserRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
 	taskRepo := repositories.NewTaskRepository(db)
+	taskStatusRepo := repositories.NewTaskStatusRepository(db)
 	petRepo := repositories.NewPetRepository(db)
 	decorationRepo := repositories.NewDecorationRepository(db)
+	timeEntryRepo := repositories.NewTimeEntryRepository(db)
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	vacationRepo := repositories.NewVacationRepository(db)
+	lairRepo := repositories.NewLairRepository(db)
+	characterRepo := repositories.NewCharacterRepository(db)
+	giftRepo := repositories.NewGiftRepository(db)
+	uow := repositories.NewUnitOfWork(db)
 
 	authService := services.NewAuthService(userRepo, jwtSecret)
-	taskService := services.NewTaskService(taskRepo, petRepo, userRepo)
-	petService := services.NewPetService(petRepo, userRepo)
-	decorationService := services.NewDecorationService(decorationRepo, userRepo)
-	syncService := services.NewSyncService(taskRepo, petRepo, decorationRepo)
-	inviteService := services.NewInviteService(jwtSecret, "http://localhost:8080")
+	taskService := services.NewTaskService(taskRepo, taskStatusRepo, petRepo, userRepo, ledgerRepo, vacationRepo, uow, store, petCfg, lootRNG)
+	petService := services.NewPetService(petRepo, ledgerRepo, taskRepo, inventoryRepo, decorationRepo, vacationRepo, uow, petCfg)
+	decorationService := services.NewDecorationService(decorationRepo, ledgerRepo, petRepo, uow)
+	itemService := services.NewItemService(inventoryRepo, ledgerRepo, uow)
+	ledgerService := services.NewLedgerService(ledgerRepo)
+	lairService := services.NewLairService(lairRepo, decorationRepo, uow)
+	achievementService := services.NewAchievementService(uow)
+	streakService := services.NewStreakService(uow, streakCfg)
+	craftingService := services.NewCraftingService(uow)
+	characterService := services.NewCharacterService(characterRepo)
+	giftService := services.NewGiftService(giftRepo, userRepo, uow, cfg.GiftsPerDay, cfg.GiftGoldPerDay)
+	vacationService := services.NewVacationService(vacationRepo, uow, cfg.VacationMaxDays, cfg.VacationsPerYear)
+	syncService := services.NewSyncService(taskRepo, taskStatusRepo, petRepo, decorationRepo, lairRepo, characterRepo, vacationRepo, petCfg)
+	inviteService := services.NewInviteService(jwtSecret, cfg.AppURL)
+	timeTrackingService := services.NewTimeTrackingService(timeEntryRepo, taskRepo, petRepo, vacationRepo, uow, petCfg)
+	attachmentService := services.NewAttachmentService(
+		attachmentRepo, taskRepo, uow, store, storage.NewURLSigner(jwtSecret),
+		cfg.AppURL, cfg.AttachmentMaxBytes, cfg.AttachmentQuotaBytes,
+	)
 
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, streakService, characterService)
 	taskHandler := handlers.NewTaskHandler(taskService)
 	petHandler := handlers.NewPetHandler(petService)
 	decorationHandler := handlers.NewDecorationHandler(decorationService)
+	shopHandler := handlers.NewShopHandler(itemService)
+	vacationHandler := handlers.NewVacationHandler(vacationService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	lairHandler := handlers.NewLairHandler(lairService)
+	achievementHandler := handlers.NewAchievementHandler(achievementService)
+	streakHandler := handlers.NewStreakHandler(streakService)
+	craftingHandler := handlers.NewCraftingHandler(craftingService)
+	giftHandler := handlers.NewGiftHandler(giftService)
 	syncHandler := handlers.NewSyncHandler(syncService)
 	inviteHandler := handlers.NewInviteHandler(inviteService)
+	timeHandler := handlers.NewTimeHandler(timeTrackingService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
 
 	// Public routes
 	auth := router.Group("/auth")
@@ -123,12 +165,20 @@ func SetupRoutesWithAuth(
 		invite.GET("/:token/qr", inviteHandler.GetInviteQR)
 	}
 
+	// Signed attachment downloads (the signature is the credential)
+	router.GET("/attachments/:id/download", attachmentHandler.DownloadAttachment)
+
 	// Protected routes with auth middleware
 	protected := router.Group("")
 	protected.Use(middleware.AuthMiddleware(authService))
 	{
 		// User profile
 		protected.GET("/me", authHandler.GetMe)
+		protected.GET("/me/transactions", ledgerHandler.GetTransactions)
+		protected.GET("/me/achievements", achievementHandler.GetAchievements)
+		protected.PUT("/me/timezone", authHandler.SetTimeZone)
+		protected.POST("/me/check-in", streakHandler.CheckIn)
+		protected.POST("/me/streak/freezes", streakHandler.BuyFreeze)
 
 		// Tasks
 		tasks := protected.Group("/tasks")
@@ -137,8 +187,38 @@ func SetupRoutesWithAuth(
 			tasks.POST("", taskHandler.CreateTask)
 			tasks.POST("/bulk", taskHandler.CreateBulkTasks)
 			tasks.POST("/:id/complete", taskHandler.CompleteTask)
+			tasks.POST("/:id/move", taskHandler.MoveTask)
+			tasks.POST("/:id/approve", taskHandler.ApproveTask)
+			tasks.POST("/:id/reject", taskHandler.RejectTask)
 			tasks.DELETE("/:id", taskHandler.DeleteTask)
+			tasks.POST("/:id/timer/start", timeHandler.StartTimer)
+			tasks.GET("/:id/time", timeHandler.GetTaskTime)
+			tasks.POST("/:id/attachments", attachmentHandler.UploadAttachment)
+			tasks.GET("/:id/attachments", attachmentHandler.GetAttachments)
+		}
+
+		// Attachments
+		protected.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+
+		// Reviews
+		protected.GET("/reviews", taskHandler.GetPendingReviews)
+
+		// Board columns
+		statuses := protected.Group("/statuses")
+		{
+			statuses.GET("", taskHandler.GetStatuses)
+			statuses.POST("", taskHandler.CreateStatus)
+			statuses.PATCH("/:id", taskHandler.UpdateStatus)
+			statuses.DELETE("/:id", taskHandler.DeleteStatus)
+		}
+
+		// Time tracking
+		timer := protected.Group("/timer")
+		{
+			timer.GET("", timeHandler.GetActiveTimer)
+			timer.POST("/stop", timeHandler.StopTimer)
 		}
+		protected.GET("/time/daily", timeHandler.GetDailyTime)
 
 		// Pet
 		pet := protected.Group("/pet")
@@ -146,7 +226,23 @@ func SetupRoutesWithAuth(
 			pet.GET("", petHandler.GetPet)
 			pet.POST("/feed", petHandler.FeedPet)
 			pet.POST("/play", petHandler.PlayWithPet)
+			pet.GET("/health", petHandler.GetPetHealth)
+			pet.POST("/revive", petHandler.RevivePet)
+			pet.POST("/use-item", petHandler.UseItem)
+			pet.GET("/events", petHandler.GetPetEvents)
 		}
+		pets := protected.Group("/pets")
+		{
+			pets.GET("", petHandler.GetPets)
+			pets.POST("/adopt", petHandler.AdoptPet)
+			pets.POST("/:id/activate", petHandler.ActivatePet)
+			pets.PATCH("/:id", petHandler.RenamePet)
+			pets.GET("/:id/evolution", petHandler.GetPetEvolution)
+			pets.POST("/:id/equip", petHandler.EquipAccessory)
+			pets.POST("/:id/unequip", petHandler.UnequipAccessory)
+		}
+		protected.GET("/species", petHandler.GetSpecies)
+		protected.GET("/categories", taskHandler.GetCategories)
 
 		// Decorations
 		decorations := protected.Group("/decorations")
@@ -155,6 +251,54 @@ func SetupRoutesWithAuth(
 			decorations.POST("/buy", decorationHandler.BuyDecoration)
 		}
 
+		// Shop and inventory
+		shop := protected.Group("/shop")
+		{
+			shop.GET("/items", shopHandler.GetItems)
+			shop.POST("/items/:id/buy", shopHandler.BuyItem)
+			shop.GET("/decorations", decorationHandler.GetShopDecorations)
+			shop.GET("/accessories", decorationHandler.GetAccessories)
+		}
+		protected.GET("/inventory", shopHandler.GetInventory)
+
+		// Crafting
+		crafting := protected.Group("/crafting")
+		{
+			crafting.GET("/recipes", craftingHandler.GetRecipes)
+			crafting.POST("/craft", craftingHandler.Craft)
+		}
+
+		// Gifts
+		gifts := protected.Group("/gifts")
+		{
+			gifts.POST("", giftHandler.SendGift)
+			gifts.GET("/inbox", giftHandler.GetInbox)
+			gifts.GET("/sent", giftHandler.GetSentGifts)
+			gifts.POST("/:id/accept", giftHandler.AcceptGift)
+			gifts.POST("/:id/decline", giftHandler.DeclineGift)
+		}
+
+		// Lair layouts
+		layouts := protected.Group("/lair/layouts")
+		{
+			layouts.GET("", lairHandler.GetLayouts)
+			layouts.POST("", lairHandler.CreateLayout)
+			layouts.GET("/:id", lairHandler.GetLayout)
+			layouts.PUT("/:id", lairHandler.SaveLayout)
+			layouts.DELETE("/:id", lairHandler.DeleteLayout)
+			layouts.POST("/:id/activate", lairHandler.ActivateLayout)
+			layouts.POST("/:id/placements", lairHandler.PlaceDecoration)
+			layouts.DELETE("/:id/placements/:decoration", lairHandler.RemoveDecoration)
+		}
+
+		// Vacation
+		vacation := protected.Group("/vacation")
+		{
+			vacation.GET("", vacationHandler.GetVacation)
+			vacation.POST("", vacationHandler.StartVacation)
+			vacation.DELETE("", vacationHandler.EndVacation)
+		}
+
 		// Sync
 		protected.POST("/sync", syncHandler.Sync)
 
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"guildquest/internal/catalog"
+	"guildquest/internal/repositories"
+)
+
+// Reading achievements shows progress but never unlocks or pays; the
+// next event does
+func TestGetAchievementsOnlyReads(t *testing.T) {
+	db := newFakeDB()
+	achievements := NewAchievementService(db.repos().Work)
+	alice := db.addUser("alice@example.com", 0)
+	db.petLevels[alice.ID] = 10
+
+	list, err := achievements.GetAchievements(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range list {
+		if a.ID == "devoted_keeper" && (a.Progress != a.Goal || a.UnlockedAt != nil) {
+			t.Fatalf("devoted_keeper shows progress %d/%d, unlocked %v", a.Progress, a.Goal, a.UnlockedAt)
+		}
+	}
+	if len(db.achievements[alice.ID]) != 0 || db.gold[alice.ID] != 0 {
+		t.Fatalf("reading unlocked %d achievements and paid %d gold", len(db.achievements[alice.ID]), db.gold[alice.ID])
+	}
+
+	err = db.repos().Work.Do(func(repos repositories.Repositories) error {
+		return achievementEngine{repos: repos}.publish(alice.ID, EventPetProgressed)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db.achievements[alice.ID]) == 0 || db.gold[alice.ID] == 0 {
+		t.Fatal("the pet event didn't unlock and pay devoted_keeper")
+	}
+}
+
+// A toy reward follows the one-of-each-toy rule; toys the user can't keep
+// are paid out at their shop price
+func TestToyRewardOwnedOnce(t *testing.T) {
+	db := newFakeDB()
+	engine := achievementEngine{repos: db.repos()}
+	ball, _ := catalog.FindItem("ball")
+	achievement := catalog.Achievement{
+		ID:     "test_toys",
+		Reward: catalog.AchievementReward{Items: map[string]int{"ball": 2}},
+	}
+
+	alice := db.addUser("alice@example.com", 0)
+	if err := engine.unlock(alice.ID, achievement, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if db.inventory[alice.ID]["ball"] != 1 || db.gold[alice.ID] != ball.Price {
+		t.Fatalf("got %d balls and %d gold, want 1 and %d", db.inventory[alice.ID]["ball"], db.gold[alice.ID], ball.Price)
+	}
+
+	bob := db.addUser("bob@example.com", 0)
+	db.inventory[bob.ID] = map[string]int{"ball": 1}
+	if err := engine.unlock(bob.ID, achievement, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if db.inventory[bob.ID]["ball"] != 1 || db.gold[bob.ID] != 2*ball.Price {
+		t.Fatalf("owner got %d balls and %d gold, want 1 and %d", db.inventory[bob.ID]["ball"], db.gold[bob.ID], 2*ball.Price)
+	}
+}
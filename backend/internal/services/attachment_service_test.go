@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"guildquest/internal/models"
+	"guildquest/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+func newAttachmentTest(t *testing.T, quota int64) (*fakeDB, AttachmentService, string, models.Task) {
+	t.Helper()
+	root := t.TempDir()
+	store, err := storage.NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := newFakeDB()
+	repos := db.repos()
+	attachments := NewAttachmentService(repos.Attachments, repos.Tasks, repos.Work, store,
+		storage.NewURLSigner("test-secret"), "https://app.example", 1<<20, quota)
+
+	user := db.addUser("alice@example.com", 0)
+	task := models.Task{ID: uuid.New(), UserID: user.ID}
+	db.tasks[task.ID] = task
+	return db, attachments, root, task
+}
+
+// download follows a signed link, optionally tampering with its query first
+func download(attachments AttachmentService, link string, tamper func(url.Values)) ([]byte, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	if tamper != nil {
+		tamper(query)
+	}
+	id := uuid.MustParse(strings.Split(strings.TrimPrefix(u.Path, "/api/v1/attachments/"), "/")[0])
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+	_, body, err := attachments.OpenAttachment(id, query.Get("variant"), expires, query.Get("signature"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func countBlobs(t *testing.T, root string) int {
+	t.Helper()
+	n := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestAttachmentSignedLinks(t *testing.T) {
+	_, attachments, _, task := newAttachmentTest(t, 1<<20)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 600, 300))); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := attachments.Upload(task.UserID, task.ID, "../../photo.png", bytes.NewReader(img.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ContentType != "image/png" || resp.ThumbnailURL == "" || strings.Contains(resp.FileName, "/") {
+		t.Fatalf("uploaded %q as %s with thumbnail link %q", resp.FileName, resp.ContentType, resp.ThumbnailURL)
+	}
+
+	original, err := download(attachments, resp.DownloadURL, nil)
+	if err != nil || !bytes.Equal(original, img.Bytes()) {
+		t.Fatalf("download link: %d bytes, %v; want the upload back", len(original), err)
+	}
+	thumb, err := download(attachments, resp.ThumbnailURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb)); err != nil || cfg.Width != thumbnailMaxSide || cfg.Height != thumbnailMaxSide/2 {
+		t.Fatalf("thumbnail is %dx%d (%v), want %dx%d", cfg.Width, cfg.Height, err, thumbnailMaxSide, thumbnailMaxSide/2)
+	}
+
+	for name, tamper := range map[string]func(url.Values){
+		"another variant":    func(q url.Values) { q.Set("variant", "thumbnail") },
+		"a later expiry":     func(q url.Values) { q.Set("expires", strconv.FormatInt(1<<40, 10)) },
+		"an expired link":    func(q url.Values) { q.Set("expires", "1") },
+		"a forged signature": func(q url.Values) { q.Set("signature", strings.Repeat("0", 64)) },
+	} {
+		if _, err := download(attachments, resp.DownloadURL, tamper); err == nil {
+			t.Errorf("download with %s succeeded", name)
+		}
+	}
+}
+
+// An upload over the quota is refused, and leaves no blobs behind
+func TestAttachmentQuota(t *testing.T) {
+	db, attachments, root, task := newAttachmentTest(t, 100)
+	text := func(n int) io.Reader { return strings.NewReader(strings.Repeat("a", n)) }
+
+	if _, err := attachments.Upload(task.UserID, task.ID, "notes.txt", text(80)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := attachments.Upload(task.UserID, task.ID, "more.txt", text(30)); err == nil {
+		t.Fatal("uploaded past the quota")
+	}
+	if len(db.attachments) != 1 || countBlobs(t, root) != 1 {
+		t.Fatalf("%d attachments and %d blobs stored, want 1 of each", len(db.attachments), countBlobs(t, root))
+	}
+}
+
+func TestAttachmentSniffsType(t *testing.T) {
+	_, attachments, root, task := newAttachmentTest(t, 1<<20)
+
+	// A renamed executable is rejected by its bytes
+	if _, err := attachments.Upload(task.UserID, task.ID, "invoice.pdf", strings.NewReader("MZ\x90\x00\x03\x00\x00\x00\x04\x00")); err == nil {
+		t.Fatal("uploaded an executable named invoice.pdf")
+	}
+	if _, err := attachments.Upload(uuid.New(), task.ID, "notes.txt", strings.NewReader("hello")); err == nil {
+		t.Fatal("uploaded to someone else's task")
+	}
+	if countBlobs(t, root) != 0 {
+		t.Fatal("refused uploads left blobs behind")
+	}
+}
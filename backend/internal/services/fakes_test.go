@@ -0,0 +1,576 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"guildquest/internal/models"
+	"guildquest/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// In-memory stand-ins for the repositories, enough to run services
+// without a database. Each embeds its interface, so a test calling a
+// method it didn't expect panics.
+
+type fakeDB struct {
+	users        map[uuid.UUID]models.User
+	gold         map[uuid.UUID]int
+	ledger       []models.GoldTransaction
+	inventory    map[uuid.UUID]map[string]int
+	pity         map[uuid.UUID]models.LootPity
+	petLevels    map[uuid.UUID]int
+	decorations  map[uuid.UUID]map[string]bool
+	gifts        map[uuid.UUID]models.Gift
+	achievements map[uuid.UUID][]models.UserAchievement
+	completed    map[uuid.UUID]int64
+	completions  map[uuid.UUID][]time.Time
+	streaks      map[uuid.UUID]models.Streak
+	vacations    []models.Vacation
+	transitions  []models.PetHealthTransition
+	petEvents    []models.PetEvent
+	interactions map[string]models.PetInteraction
+	tasks        map[uuid.UUID]models.Task
+	attachments  map[uuid.UUID]models.Attachment
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		users:        map[uuid.UUID]models.User{},
+		gold:         map[uuid.UUID]int{},
+		inventory:    map[uuid.UUID]map[string]int{},
+		pity:         map[uuid.UUID]models.LootPity{},
+		petLevels:    map[uuid.UUID]int{},
+		decorations:  map[uuid.UUID]map[string]bool{},
+		gifts:        map[uuid.UUID]models.Gift{},
+		achievements: map[uuid.UUID][]models.UserAchievement{},
+		completed:    map[uuid.UUID]int64{},
+		completions:  map[uuid.UUID][]time.Time{},
+		streaks:      map[uuid.UUID]models.Streak{},
+		interactions: map[string]models.PetInteraction{},
+		tasks:        map[uuid.UUID]models.Task{},
+		attachments:  map[uuid.UUID]models.Attachment{},
+	}
+}
+
+// addUser creates a user with the given gold
+func (db *fakeDB) addUser(email string, gold int) models.User {
+	user := models.User{ID: uuid.New(), Email: email}
+	db.users[user.ID] = user
+	db.gold[user.ID] = gold
+	return user
+}
+
+func (db *fakeDB) repos() repositories.Repositories {
+	return repositories.Repositories{
+		Users:        fakeUsers{db: db},
+		Ledger:       fakeLedger{db: db},
+		Achievements: fakeAchievements{db: db},
+		Streaks:      fakeStreaks{db: db},
+		Vacations:    fakeVacations{db: db},
+		Tasks:        fakeTasks{db: db},
+		Attachments:  fakeAttachments{db: db},
+		Loot:         fakeLoot{db: db},
+		Pets:         fakePets{db: db},
+		Inventory:    fakeInventory{db: db},
+		Decorations:  fakeDecorations{db: db},
+		Lairs:        fakeLairs{db: db},
+		Gifts:        fakeGifts{db: db},
+		Work:         fakeUnitOfWork{db: db},
+	}
+}
+
+// clone copies the data, for rolling back a failed unit of work
+func (db *fakeDB) clone() *fakeDB {
+	c := newFakeDB()
+	for k, v := range db.users {
+		c.users[k] = v
+	}
+	for k, v := range db.gold {
+		c.gold[k] = v
+	}
+	c.ledger = append(c.ledger, db.ledger...)
+	for k, v := range db.inventory {
+		c.inventory[k] = map[string]int{}
+		for item, n := range v {
+			c.inventory[k][item] = n
+		}
+	}
+	for k, v := range db.pity {
+		c.pity[k] = v
+	}
+	for k, v := range db.petLevels {
+		c.petLevels[k] = v
+	}
+	for k, v := range db.decorations {
+		c.decorations[k] = map[string]bool{}
+		for d := range v {
+			c.decorations[k][d] = true
+		}
+	}
+	for k, v := range db.gifts {
+		c.gifts[k] = v
+	}
+	for k, v := range db.achievements {
+		c.achievements[k] = append([]models.UserAchievement(nil), v...)
+	}
+	for k, v := range db.completed {
+		c.completed[k] = v
+	}
+	for k, v := range db.completions {
+		c.completions[k] = append([]time.Time(nil), v...)
+	}
+	for k, v := range db.streaks {
+		c.streaks[k] = v
+	}
+	c.vacations = append(c.vacations, db.vacations...)
+	c.transitions = append(c.transitions, db.transitions...)
+	c.petEvents = append(c.petEvents, db.petEvents...)
+	for k, v := range db.interactions {
+		c.interactions[k] = v
+	}
+	for k, v := range db.tasks {
+		c.tasks[k] = v
+	}
+	for k, v := range db.attachments {
+		c.attachments[k] = v
+	}
+	return c
+}
+
+var errFakeNotFound = errors.New("record not found")
+
+type fakeUnitOfWork struct {
+	db *fakeDB
+}
+
+func (u fakeUnitOfWork) Do(fn func(repos repositories.Repositories) error) error {
+	snapshot := u.db.clone()
+	if err := fn(u.db.repos()); err != nil {
+		*u.db = *snapshot
+		return err
+	}
+	return nil
+}
+
+type fakeUsers struct {
+	repositories.UserRepository
+	db *fakeDB
+}
+
+func (r fakeUsers) FindByID(id uuid.UUID) (*models.User, error) {
+	user, ok := r.db.users[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	user.Gold = r.db.gold[id]
+	return &user, nil
+}
+
+func (r fakeUsers) FindByEmail(email string) (*models.User, error) {
+	for id, user := range r.db.users {
+		if user.Email == email {
+			return r.FindByID(id)
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (r fakeUsers) GetGold(userID uuid.UUID) (int, error) {
+	return r.db.gold[userID], nil
+}
+
+// Lock is a no-op: the fakes run one request at a time
+func (r fakeUsers) Lock(ids ...uuid.UUID) error {
+	return nil
+}
+
+type fakeLedger struct {
+	repositories.LedgerRepository
+	db *fakeDB
+}
+
+func (r fakeLedger) Apply(entry *models.GoldTransaction) (bool, error) {
+	if entry.IdempotencyKey != nil {
+		for _, e := range r.db.ledger {
+			if e.UserID == entry.UserID && e.IdempotencyKey != nil && *e.IdempotencyKey == *entry.IdempotencyKey {
+				*entry = e
+				return false, nil
+			}
+		}
+	}
+
+	balance := r.db.gold[entry.UserID] + entry.Amount
+	if entry.Amount < 0 && balance < 0 {
+		return false, repositories.ErrInsufficientGold
+	}
+
+	r.db.gold[entry.UserID] = balance
+	entry.ID, entry.Balance, entry.CreatedAt = uuid.New(), balance, time.Now()
+	r.db.ledger = append(r.db.ledger, *entry)
+	return true, nil
+}
+
+type fakeInventory struct {
+	repositories.InventoryRepository
+	db *fakeDB
+}
+
+func (r fakeInventory) Quantity(userID uuid.UUID, itemID string) (int, error) {
+	return r.db.inventory[userID][itemID], nil
+}
+
+func (r fakeInventory) Add(userID uuid.UUID, itemID string, quantity int) error {
+	if r.db.inventory[userID] == nil {
+		r.db.inventory[userID] = map[string]int{}
+	}
+	r.db.inventory[userID][itemID] += quantity
+	return nil
+}
+
+func (r fakeInventory) Remove(userID uuid.UUID, itemID string, quantity int) (bool, error) {
+	if r.db.inventory[userID][itemID] < quantity {
+		return false, nil
+	}
+	r.db.inventory[userID][itemID] -= quantity
+	return true, nil
+}
+
+type fakeLoot struct {
+	repositories.LootRepository
+	db *fakeDB
+}
+
+func (r fakeLoot) Lock(userID uuid.UUID) (*models.LootPity, error) {
+	pity := r.db.pity[userID]
+	pity.UserID = userID
+	return &pity, nil
+}
+
+func (r fakeLoot) Save(pity *models.LootPity) error {
+	r.db.pity[pity.UserID] = *pity
+	return nil
+}
+
+type fakePets struct {
+	repositories.PetRepository
+	db *fakeDB
+}
+
+func (r fakePets) MaxLevelByUserID(userID uuid.UUID) (int, error) {
+	return r.db.petLevels[userID], nil
+}
+
+// SaveDecay always wins: the tests advance one pet at a time
+func (r fakePets) SaveDecay(pet *models.Pet, prev *time.Time) (bool, error) {
+	return true, nil
+}
+
+func (r fakePets) AddHealthTransitions(transitions []models.PetHealthTransition) error {
+	r.db.transitions = append(r.db.transitions, transitions...)
+	return nil
+}
+
+func (r fakePets) UpdateInteraction(petID uuid.UUID, action string, fn func(interaction *models.PetInteraction) error) error {
+	key := petID.String() + "/" + action
+	interaction, ok := r.db.interactions[key]
+	if !ok {
+		// A zero window start opens a window on first use, whatever clock
+		// the test runs on
+		interaction = models.PetInteraction{PetID: petID, Action: action}
+	}
+	if err := fn(&interaction); err != nil {
+		return err
+	}
+	r.db.interactions[key] = interaction
+	return nil
+}
+
+func (r fakePets) AddEvents(events []models.PetEvent) error {
+	r.db.petEvents = append(r.db.petEvents, events...)
+	return nil
+}
+
+type fakeAchievements struct {
+	repositories.AchievementRepository
+	db *fakeDB
+}
+
+func (r fakeAchievements) FindByUserID(userID uuid.UUID) ([]models.UserAchievement, error) {
+	return r.db.achievements[userID], nil
+}
+
+func (r fakeAchievements) Unlock(achievement *models.UserAchievement) (bool, error) {
+	for _, a := range r.db.achievements[achievement.UserID] {
+		if a.AchievementID == achievement.AchievementID {
+			return false, nil
+		}
+	}
+	r.db.achievements[achievement.UserID] = append(r.db.achievements[achievement.UserID], *achievement)
+	return true, nil
+}
+
+type fakeTasks struct {
+	repositories.TaskRepository
+	db *fakeDB
+}
+
+func (r fakeTasks) CountCompleted(userID uuid.UUID) (int64, error) {
+	return r.db.completed[userID], nil
+}
+
+func (r fakeTasks) FindCompletionTimes(userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	for _, at := range r.db.completions[userID] {
+		if !at.Before(since) {
+			times = append(times, at)
+		}
+	}
+	return times, nil
+}
+
+func (r fakeTasks) FindByID(id uuid.UUID) (*models.Task, error) {
+	task, ok := r.db.tasks[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	return &task, nil
+}
+
+func (r fakeTasks) FindByStatusID(statusID uuid.UUID) ([]models.Task, error) {
+	var column []models.Task
+	for _, task := range r.db.tasks {
+		if task.StatusID != nil && *task.StatusID == statusID {
+			column = append(column, task)
+		}
+	}
+	sort.Slice(column, func(i, j int) bool { return column[i].Rank < column[j].Rank })
+	return column, nil
+}
+
+func (r fakeTasks) Update(task *models.Task) error {
+	r.db.tasks[task.ID] = *task
+	return nil
+}
+
+type fakeAttachments struct {
+	repositories.AttachmentRepository
+	db *fakeDB
+}
+
+func (r fakeAttachments) Create(attachment *models.Attachment) error {
+	r.db.attachments[attachment.ID] = *attachment
+	return nil
+}
+
+func (r fakeAttachments) FindByID(id uuid.UUID) (*models.Attachment, error) {
+	attachment, ok := r.db.attachments[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	return &attachment, nil
+}
+
+func (r fakeAttachments) SumSizeByUserID(userID uuid.UUID) (int64, error) {
+	var total int64
+	for _, a := range r.db.attachments {
+		if a.UserID == userID {
+			total += a.Size
+		}
+	}
+	return total, nil
+}
+
+type fakeStreaks struct {
+	repositories.StreakRepository
+	db *fakeDB
+}
+
+func (r fakeStreaks) FindByUserID(userID uuid.UUID) (*models.Streak, error) {
+	streak, ok := r.db.streaks[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &streak, nil
+}
+
+func (r fakeStreaks) Lock(userID uuid.UUID) (*models.Streak, error) {
+	if _, ok := r.db.streaks[userID]; !ok {
+		r.db.streaks[userID] = models.Streak{UserID: userID}
+	}
+	return r.FindByUserID(userID)
+}
+
+func (r fakeStreaks) Save(streak *models.Streak) error {
+	r.db.streaks[streak.UserID] = *streak
+	return nil
+}
+
+type fakeVacations struct {
+	repositories.VacationRepository
+	db *fakeDB
+}
+
+func (r fakeVacations) FindOverlapping(userID uuid.UUID, from, to time.Time) ([]models.Vacation, error) {
+	var overlapping []models.Vacation
+	for _, v := range r.db.vacations {
+		if v.UserID == userID && v.StartsAt.Before(to) && v.End().After(from) {
+			overlapping = append(overlapping, v)
+		}
+	}
+	return overlapping, nil
+}
+
+func (r fakeVacations) FindScheduled(userID uuid.UUID, now time.Time) (*models.Vacation, error) {
+	var scheduled *models.Vacation
+	for i, v := range r.db.vacations {
+		if v.UserID == userID && v.End().After(now) && (scheduled == nil || v.StartsAt.Before(scheduled.StartsAt)) {
+			scheduled = &r.db.vacations[i]
+		}
+	}
+	if scheduled == nil {
+		return nil, nil
+	}
+	vacation := *scheduled
+	return &vacation, nil
+}
+
+func (r fakeVacations) CountStartedSince(userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	for _, v := range r.db.vacations {
+		if v.UserID == userID && !v.StartsAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r fakeVacations) Create(vacation *models.Vacation) error {
+	vacation.ID = uuid.New()
+	r.db.vacations = append(r.db.vacations, *vacation)
+	return nil
+}
+
+func (r fakeVacations) Update(vacation *models.Vacation) error {
+	for i := range r.db.vacations {
+		if r.db.vacations[i].ID == vacation.ID {
+			r.db.vacations[i] = *vacation
+		}
+	}
+	return nil
+}
+
+func (r fakeVacations) Delete(id uuid.UUID) error {
+	kept := r.db.vacations[:0]
+	for _, v := range r.db.vacations {
+		if v.ID != id {
+			kept = append(kept, v)
+		}
+	}
+	r.db.vacations = kept
+	return nil
+}
+
+type fakeDecorations struct {
+	repositories.DecorationRepository
+	db *fakeDB
+}
+
+func (r fakeDecorations) Create(decoration *models.Decoration) error {
+	if r.db.decorations[decoration.UserID][decoration.Decoration] {
+		return errors.New("duplicate key value violates unique constraint")
+	}
+	if r.db.decorations[decoration.UserID] == nil {
+		r.db.decorations[decoration.UserID] = map[string]bool{}
+	}
+	r.db.decorations[decoration.UserID][decoration.Decoration] = true
+	return nil
+}
+
+func (r fakeDecorations) Exists(userID uuid.UUID, decoration string) (bool, error) {
+	return r.db.decorations[userID][decoration], nil
+}
+
+func (r fakeDecorations) CountByUserID(userID uuid.UUID) (int64, error) {
+	return int64(len(r.db.decorations[userID])), nil
+}
+
+func (r fakeDecorations) Delete(userID uuid.UUID, decoration string) (bool, error) {
+	if !r.db.decorations[userID][decoration] {
+		return false, nil
+	}
+	delete(r.db.decorations[userID], decoration)
+	return true, nil
+}
+
+type fakeLairs struct {
+	repositories.LairRepository
+	db *fakeDB
+}
+
+func (r fakeLairs) Unplace(userID uuid.UUID, decoration string) error {
+	return nil
+}
+
+type fakeGifts struct {
+	repositories.GiftRepository
+	db *fakeDB
+}
+
+func (r fakeGifts) Create(gift *models.Gift) error {
+	gift.CreatedAt = time.Now()
+	r.db.gifts[gift.ID] = *gift
+	return nil
+}
+
+func (r fakeGifts) FindByID(id uuid.UUID) (*models.Gift, error) {
+	gift, ok := r.db.gifts[id]
+	if !ok {
+		return nil, nil
+	}
+	return &gift, nil
+}
+
+func (r fakeGifts) FindByIdempotencyKey(senderID uuid.UUID, key string) (*models.Gift, error) {
+	for _, gift := range r.db.gifts {
+		if gift.SenderID == senderID && gift.IdempotencyKey != nil && *gift.IdempotencyKey == key {
+			return &gift, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r fakeGifts) Respond(gift *models.Gift, status string, at time.Time) (bool, error) {
+	stored := r.db.gifts[gift.ID]
+	if stored.Status != models.GiftPending {
+		return false, nil
+	}
+	stored.Status, stored.RespondedAt = status, &at
+	r.db.gifts[gift.ID] = stored
+	gift.Status, gift.RespondedAt = status, &at
+	return true, nil
+}
+
+func (r fakeGifts) SentSince(senderID uuid.UUID, since time.Time) (int64, int, error) {
+	var count int64
+	gold := 0
+	for _, g := range r.db.gifts {
+		if g.SenderID == senderID && !g.CreatedAt.Before(since) {
+			count++
+			gold += g.Gold
+		}
+	}
+	return count, gold, nil
+}
+
+func (r fakeGifts) GoldReceivedSince(recipientID uuid.UUID, since time.Time) (int, error) {
+	gold := 0
+	for _, g := range r.db.gifts {
+		if g.RecipientID == recipientID && !g.CreatedAt.Before(since) && g.Status != models.GiftDeclined {
+			gold += g.Gold
+		}
+	}
+	return gold, nil
+}
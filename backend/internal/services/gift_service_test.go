@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"guildquest/internal/models"
+)
+
+func newGiftTest() (*fakeDB, DecorationService, GiftService) {
+	db := newFakeDB()
+	repos := db.repos()
+	decorations := NewDecorationService(repos.Decorations, repos.Ledger, repos.Pets, repos.Work)
+	gifts := NewGiftService(repos.Gifts, repos.Users, repos.Work, 10, 500)
+	return db, decorations, gifts
+}
+
+// A decoration given away can be bought again, and is charged for again
+func TestRebuyGiftedDecoration(t *testing.T) {
+	db, decorations, gifts := newGiftTest()
+	alice := db.addUser("alice@example.com", 1000)
+	bob := db.addUser("bob@example.com", 0)
+	db.petLevels[alice.ID] = 10
+
+	if err := decorations.BuyDecoration(alice.ID, "bonsai"); err != nil {
+		t.Fatalf("first purchase: %v", err)
+	}
+	paid := 1000 - db.gold[alice.ID]
+	if paid <= 0 || !db.decorations[alice.ID]["bonsai"] {
+		t.Fatalf("first purchase charged %d and left ownership %v", paid, db.decorations[alice.ID]["bonsai"])
+	}
+
+	if err := decorations.BuyDecoration(alice.ID, "bonsai"); err == nil {
+		t.Fatal("bought a decoration already owned")
+	}
+	if db.gold[alice.ID] != 1000-paid {
+		t.Fatalf("refused purchase left %d gold, want %d", db.gold[alice.ID], 1000-paid)
+	}
+
+	gift, err := gifts.SendGift(alice.ID, models.SendGiftRequest{RecipientEmail: bob.Email, Decoration: "bonsai"}, "")
+	if err != nil {
+		t.Fatalf("gifting: %v", err)
+	}
+	if db.decorations[alice.ID]["bonsai"] {
+		t.Fatal("sender still owns the gifted decoration")
+	}
+
+	if err := decorations.BuyDecoration(alice.ID, "bonsai"); err != nil {
+		t.Fatalf("buying again: %v", err)
+	}
+	if db.gold[alice.ID] != 1000-2*paid || !db.decorations[alice.ID]["bonsai"] {
+		t.Fatalf("buying again left %d gold and ownership %v, want %d and true",
+			db.gold[alice.ID], db.decorations[alice.ID]["bonsai"], 1000-2*paid)
+	}
+
+	if _, err := gifts.AcceptGift(bob.ID, gift.ID); err != nil {
+		t.Fatalf("accepting: %v", err)
+	}
+	if !db.decorations[bob.ID]["bonsai"] {
+		t.Fatal("recipient doesn't own the accepted decoration")
+	}
+}
+
+func TestGiftIdempotencyKey(t *testing.T) {
+	db, _, gifts := newGiftTest()
+	alice := db.addUser("alice@example.com", 1000)
+	bob := db.addUser("bob@example.com", 0)
+	req := models.SendGiftRequest{RecipientEmail: bob.Email, Gold: 50}
+
+	first, err := gifts.SendGift(alice.ID, req, "key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := gifts.SendGift(alice.ID, req, "key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.ID != first.ID || db.gold[alice.ID] != 950 {
+		t.Fatalf("retry sent gift %s leaving %d gold, want gift %s and 950", retry.ID, db.gold[alice.ID], first.ID)
+	}
+
+	req.Gold = 80
+	if _, err := gifts.SendGift(alice.ID, req, "key-1"); !errors.Is(err, ErrGiftKeyReused) {
+		t.Fatalf("reusing the key for another gift returned %v, want ErrGiftKeyReused", err)
+	}
+	if db.gold[alice.ID] != 950 || len(db.gifts) != 1 {
+		t.Fatalf("reused key left %d gold and %d gifts, want 950 and 1", db.gold[alice.ID], len(db.gifts))
+	}
+
+	// Gifts without gold are just as idempotent
+	db.inventory[alice.ID] = map[string]int{"kibble": 3}
+	itemReq := models.SendGiftRequest{RecipientEmail: bob.Email, ItemID: "kibble", Quantity: 2}
+	for i := 0; i < 2; i++ {
+		if _, err := gifts.SendGift(alice.ID, itemReq, "key-2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if db.inventory[alice.ID]["kibble"] != 1 || len(db.gifts) != 2 {
+		t.Fatalf("retried item gift left %d kibble and %d gifts, want 1 and 2", db.inventory[alice.ID]["kibble"], len(db.gifts))
+	}
+}
+
+func TestDeclinedGiftGoesBack(t *testing.T) {
+	db, _, gifts := newGiftTest()
+	alice := db.addUser("alice@example.com", 1000)
+	bob := db.addUser("bob@example.com", 0)
+	db.inventory[alice.ID] = map[string]int{"kibble": 3}
+
+	gift, err := gifts.SendGift(alice.ID, models.SendGiftRequest{RecipientEmail: bob.Email, Gold: 100, ItemID: "kibble", Quantity: 2}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.gold[alice.ID] != 900 || db.inventory[alice.ID]["kibble"] != 1 {
+		t.Fatalf("sending left %d gold and %d kibble", db.gold[alice.ID], db.inventory[alice.ID]["kibble"])
+	}
+
+	if _, err := gifts.DeclineGift(bob.ID, gift.ID); err != nil {
+		t.Fatal(err)
+	}
+	if db.gold[alice.ID] != 1000 || db.inventory[alice.ID]["kibble"] != 3 || db.gold[bob.ID] != 0 {
+		t.Fatalf("declining left the sender %d gold and %d kibble, the recipient %d gold",
+			db.gold[alice.ID], db.inventory[alice.ID]["kibble"], db.gold[bob.ID])
+	}
+
+	if _, err := gifts.AcceptGift(bob.ID, gift.ID); err == nil {
+		t.Fatal("accepted a gift already declined")
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"guildquest/internal/catalog"
+)
+
+func TestBuyItemRetryBuysOnce(t *testing.T) {
+	db := newFakeDB()
+	repos := db.repos()
+	items := NewItemService(repos.Inventory, repos.Ledger, repos.Work)
+	alice := db.addUser("alice@example.com", 500)
+	kibble, _ := catalog.FindItem("kibble")
+
+	for i := 0; i < 2; i++ {
+		if _, err := items.BuyItem(alice.ID, "kibble", 2, "key-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if db.inventory[alice.ID]["kibble"] != 2 || db.gold[alice.ID] != 500-2*kibble.Price || len(db.ledger) != 1 {
+		t.Fatalf("retried purchase left %d kibble, %d gold and %d ledger entries",
+			db.inventory[alice.ID]["kibble"], db.gold[alice.ID], len(db.ledger))
+	}
+
+	// The key is scoped to the item
+	if _, err := items.BuyItem(alice.ID, "ball", 1, "key-1"); err != nil {
+		t.Fatal(err)
+	}
+	if db.inventory[alice.ID]["ball"] != 1 || len(db.ledger) != 2 {
+		t.Fatalf("reusing the key for another item left %d balls and %d ledger entries", db.inventory[alice.ID]["ball"], len(db.ledger))
+	}
+}
+
+func TestBuyItemNeedsGold(t *testing.T) {
+	db := newFakeDB()
+	repos := db.repos()
+	items := NewItemService(repos.Inventory, repos.Ledger, repos.Work)
+	alice := db.addUser("alice@example.com", 10)
+
+	if _, err := items.BuyItem(alice.ID, "ball", 1, ""); err == nil {
+		t.Fatal("bought a toy without enough gold")
+	}
+	if db.inventory[alice.ID]["ball"] != 0 || db.gold[alice.ID] != 10 || len(db.ledger) != 0 {
+		t.Fatalf("refused purchase left %d balls, %d gold and %d ledger entries",
+			db.inventory[alice.ID]["ball"], db.gold[alice.ID], len(db.ledger))
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"guildquest/internal/catalog"
+	"guildquest/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var testDrops = []catalog.LootDrop{
+	{Gold: 10, Weight: 60},
+	{Item: "kibble", Quantity: 1, Weight: 30},
+	{Item: "feast", Quantity: 1, Weight: 8, Rare: true},
+	{Item: "phoenix_feather", Quantity: 1, Weight: 2, Rare: true},
+}
+
+func TestLootPickIsWeightedAndReproducible(t *testing.T) {
+	a := lootRoller{rng: NewLootRNG(42)}
+	b := lootRoller{rng: NewLootRNG(42)}
+
+	const picks = 10000
+	counts := map[int]int{}
+	for i := 0; i < picks; i++ {
+		da, db := a.pick(testDrops, false), b.pick(testDrops, false)
+		if da != db {
+			t.Fatalf("pick %d differs between rollers with the same seed", i)
+		}
+		for j := range testDrops {
+			if da == &testDrops[j] {
+				counts[j]++
+			}
+		}
+	}
+
+	for j, d := range testDrops {
+		want := picks * d.Weight / 100
+		if diff := counts[j] - want; diff < -want/5-20 || diff > want/5+20 {
+			t.Errorf("drop %d picked %d times, want about %d", j, counts[j], want)
+		}
+	}
+}
+
+func TestLootPickRareOnly(t *testing.T) {
+	l := lootRoller{rng: NewLootRNG(7)}
+
+	feathers := 0
+	for i := 0; i < 1000; i++ {
+		drop := l.pick(testDrops, true)
+		if drop == nil || !drop.Rare {
+			t.Fatalf("rare-only pick returned %+v", drop)
+		}
+		if drop.Item == "phoenix_feather" {
+			feathers++
+		}
+	}
+	// Weighted 2:8 among the rare drops
+	if feathers < 120 || feathers > 280 {
+		t.Errorf("phoenix feather picked %d of 1000 times, want about 200", feathers)
+	}
+
+	if drop := l.pick(testDrops[:2], true); drop != nil {
+		t.Errorf("rare-only pick without rare drops returned %+v", drop)
+	}
+}
+
+func TestLootPityGuaranteesRareDrop(t *testing.T) {
+	db := newFakeDB()
+	l := lootRoller{repos: db.repos(), rng: NewLootRNG(1)}
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Reward: 100}
+	tier := catalog.LootTierFor(task.Reward)
+
+	// The next quest is the PityAfter-th without a rare drop
+	db.pity[task.UserID] = models.LootPity{UserID: task.UserID, SinceRare: tier.PityAfter - 1}
+	drop, err := l.roll(task, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drop == nil || !drop.Rare || !drop.Pity {
+		t.Fatalf("roll at the pity threshold dropped %+v, want a rare pity drop", drop)
+	}
+	if since := db.pity[task.UserID].SinceRare; since != 0 {
+		t.Errorf("pity counter is %d after a rare drop, want 0", since)
+	}
+
+	// However unlucky the rolls, no run without a rare drop outlasts it
+	since := 0
+	for i := 0; i < 2000; i++ {
+		task.ID = uuid.New()
+		drop, err := l.roll(task, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if drop != nil && drop.Rare {
+			since = 0
+			continue
+		}
+		since++
+		if since >= tier.PityAfter {
+			t.Fatalf("%d quests in a row without a rare drop, pity is %d", since, tier.PityAfter)
+		}
+	}
+}
+
+func TestLootPerceptionRaisesDropChance(t *testing.T) {
+	drops := func(character *models.Character) int {
+		db := newFakeDB()
+		l := lootRoller{repos: db.repos(), rng: NewLootRNG(99)}
+		task := &models.Task{UserID: uuid.New(), Reward: 0}
+		bonus := characterBonuses(character).Loot
+
+		n := 0
+		for i := 0; i < 5000; i++ {
+			task.ID = uuid.New()
+			drop, err := l.roll(task, bonus)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if drop != nil && !drop.Pity {
+				n++
+			}
+		}
+		return n
+	}
+
+	base := drops(&models.Character{})
+	perceptive := drops(&models.Character{Perception: 2 * attributePointsPerPercent * attributeBonusCap})
+	// The capped bonus makes drops a quarter likelier
+	if base == 0 || perceptive < base*11/10 {
+		t.Errorf("a perceptive character got loot %d times against %d without, want about a quarter more", perceptive, base)
+	}
+}
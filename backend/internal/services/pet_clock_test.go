@@ -0,0 +1,356 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"guildquest/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// clockStart is when the test pets last decayed
+var clockStart = time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+func newClockTest() (*fakeDB, petClock) {
+	db := newFakeDB()
+	repos := db.repos()
+	cfg := PetConfig{
+		DecayInterval:   time.Hour,
+		HungerDecay:     1,
+		HappinessDecay:  1,
+		SickAfter:       6 * time.Hour,
+		FaintAfter:      12 * time.Hour,
+		SickExpLoss:     5,
+		CureHunger:      20,
+		ReviveHappiness: 40,
+		Progression:     PetProgression{BaseExp: 100, ExpGrowth: 1.5},
+	}
+	return db, petClock{petRepo: repos.Pets, vacationRepo: repos.Vacations, cfg: cfg}
+}
+
+func newClockPet(species string, hunger, happiness int) *models.Pet {
+	decayed := clockStart
+	return &models.Pet{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Type:      species,
+		Level:     1,
+		Hunger:    hunger,
+		Happiness: happiness,
+		Health:    PetHealthy,
+		DecayedAt: &decayed,
+	}
+}
+
+func advanceTo(t *testing.T, clock petClock, pet *models.Pet, at time.Time) {
+	t.Helper()
+	if _, err := clock.advance(pet, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecayPoints(t *testing.T) {
+	tests := []struct {
+		steps       int
+		rate, carry float64
+		points      int
+		carried     float64
+	}{
+		{steps: 3, rate: 1, points: 3},
+		{steps: 3, rate: 0.5, points: 1, carried: 0.5},
+		{steps: 1, rate: 0.5, carry: 0.5, points: 1},
+		{steps: 10, rate: 0.1, points: 1},
+		{steps: 0, rate: 2, carry: 0.25, carried: 0.25},
+	}
+	for _, tt := range tests {
+		points, carried := decayPoints(tt.steps, tt.rate, tt.carry)
+		if points != tt.points || carried < tt.carried-1e-9 || carried > tt.carried+1e-9 {
+			t.Errorf("decayPoints(%d, %v, %v) = %d, %v; want %d, %v", tt.steps, tt.rate, tt.carry, points, carried, tt.points, tt.carried)
+		}
+	}
+}
+
+func TestDecayStatFloor(t *testing.T) {
+	if got := decayStat(50, 10, 0); got != 40 {
+		t.Errorf("decayStat(50, 10, 0) = %d, want 40", got)
+	}
+	if got := decayStat(15, 10, 10); got != 10 {
+		t.Errorf("decayStat(15, 10, 10) = %d, want the floor", got)
+	}
+	// Stats already under the floor (say, from before it was raised) stay put
+	if got := decayStat(5, 10, 10); got != 5 {
+		t.Errorf("decayStat(5, 10, 10) = %d, want 5", got)
+	}
+}
+
+// Slow species lose fractions of a point per interval; the fractions carry
+// over, so reading the pet often loses no decay
+func TestDecayCarriesFractions(t *testing.T) {
+	_, clock := newClockTest()
+	often := newClockPet("golem", 50, 50)
+	once := newClockPet("golem", 50, 50)
+
+	for h := 1; h <= 8; h++ {
+		advanceTo(t, clock, often, clockStart.Add(time.Duration(h)*time.Hour))
+	}
+	advanceTo(t, clock, once, clockStart.Add(8*time.Hour))
+
+	// Golems decay at half speed for hunger, three quarters for happiness
+	if often.Hunger != 46 || often.Happiness != 44 {
+		t.Fatalf("read hourly: hunger %d, happiness %d; want 46 and 44", often.Hunger, often.Happiness)
+	}
+	if once.Hunger != often.Hunger || once.Happiness != often.Happiness {
+		t.Fatalf("read once: hunger %d, happiness %d; want the same as hourly", once.Hunger, once.Happiness)
+	}
+}
+
+// Only whole intervals decay; the rest of the last one counts next time
+func TestDecayKeepsPartialIntervals(t *testing.T) {
+	_, clock := newClockTest()
+	pet := newClockPet("dragon", 50, 50)
+
+	advanceTo(t, clock, pet, clockStart.Add(150*time.Minute))
+	if pet.Hunger != 48 || !pet.DecayedAt.Equal(clockStart.Add(2*time.Hour)) {
+		t.Fatalf("after 2.5h: hunger %d decayed up to %s, want 48 up to 2h in", pet.Hunger, pet.DecayedAt)
+	}
+
+	advanceTo(t, clock, pet, clockStart.Add(180*time.Minute))
+	if pet.Hunger != 47 {
+		t.Fatalf("after 3h: hunger %d, want 47", pet.Hunger)
+	}
+}
+
+func TestHealthStarvingPetSickensThenFaints(t *testing.T) {
+	_, clock := newClockTest()
+	pet := newClockPet("dragon", 0, 50)
+	starving := clockStart
+	pet.StarvingSince = &starving
+
+	if transitions := clock.updateHealth(pet, clockStart.Add(5*time.Hour), nil); len(transitions) != 0 || pet.Health != PetHealthy {
+		t.Fatalf("starving 5h: %d transitions, health %s; want still healthy", len(transitions), pet.Health)
+	}
+
+	// Both transitions are stamped with when they happened, not when seen
+	transitions := clock.updateHealth(pet, clockStart.Add(20*time.Hour), nil)
+	if len(transitions) != 2 || pet.Health != PetFainted {
+		t.Fatalf("starving 20h: %d transitions, health %s; want 2 and fainted", len(transitions), pet.Health)
+	}
+	if !transitions[0].CreatedAt.Equal(clockStart.Add(6*time.Hour)) || transitions[0].To != PetSick {
+		t.Fatalf("first transition to %s at %s, want sick at 6h", transitions[0].To, transitions[0].CreatedAt)
+	}
+	if !transitions[1].CreatedAt.Equal(clockStart.Add(18*time.Hour)) || transitions[1].From != PetSick {
+		t.Fatalf("second transition from %s at %s, want from sick at 18h", transitions[1].From, transitions[1].CreatedAt)
+	}
+}
+
+func TestHealthSickPetRecoversWhenFed(t *testing.T) {
+	_, clock := newClockTest()
+	pet := newClockPet("dragon", 10, 50)
+	sickSince := clockStart
+	pet.Health, pet.HealthChangedAt, pet.StarvingSince = PetSick, &sickSince, &sickSince
+
+	// Fed a little: no longer starving, but not enough to recover
+	if transitions := clock.updateHealth(pet, clockStart.Add(time.Hour), nil); len(transitions) != 0 || pet.Health != PetSick {
+		t.Fatalf("hunger 10: %d transitions, health %s; want still sick", len(transitions), pet.Health)
+	}
+	// ... and it doesn't faint while it has food in it
+	if clock.updateHealth(pet, clockStart.Add(30*time.Hour), nil); pet.Health != PetSick {
+		t.Fatalf("hunger 10 after 30h: health %s, want still sick", pet.Health)
+	}
+
+	pet.Hunger = clock.cfg.CureHunger
+	transitions := clock.updateHealth(pet, clockStart.Add(31*time.Hour), nil)
+	if len(transitions) != 1 || pet.Health != PetHealthy || pet.StarvingSince != nil {
+		t.Fatalf("fed to %d: %d transitions, health %s, starving since %v; want recovered", pet.Hunger, len(transitions), pet.Health, pet.StarvingSince)
+	}
+}
+
+// Food doesn't wake a fainted pet; only a revival does
+func TestHealthFaintedPetNeedsRevival(t *testing.T) {
+	_, clock := newClockTest()
+	pet := newClockPet("dragon", 100, 0)
+	fainted := clockStart
+	pet.Health, pet.HealthChangedAt, pet.RevivalProgress = PetFainted, &fainted, 2
+
+	if transitions := clock.updateHealth(pet, clockStart.Add(time.Hour), nil); len(transitions) != 0 || pet.Health != PetFainted {
+		t.Fatalf("fed fainted pet: %d transitions, health %s; want still fainted", len(transitions), pet.Health)
+	}
+
+	pet.Hunger = 0
+	now := clockStart.Add(2 * time.Hour)
+	transition := clock.revive(pet, now, "revived with gold")
+	if transition.From != PetFainted || pet.Health != PetHealthy {
+		t.Fatalf("revival went from %s to %s, want fainted to healthy", transition.From, pet.Health)
+	}
+	if pet.Hunger != clock.cfg.CureHunger || pet.Happiness != clock.cfg.ReviveHappiness || pet.RevivalProgress != 0 {
+		t.Fatalf("revived with hunger %d, happiness %d, progress %d; want %d, %d, 0",
+			pet.Hunger, pet.Happiness, pet.RevivalProgress, clock.cfg.CureHunger, clock.cfg.ReviveHappiness)
+	}
+	if !pet.DecayedAt.Equal(now) {
+		t.Fatalf("revived pet decays from %s, want %s", pet.DecayedAt, now)
+	}
+}
+
+// A sick pet loses EXP only for the intervals it spent sick
+func TestDecayDrainsExpWhileSick(t *testing.T) {
+	db, clock := newClockTest()
+	pet := newClockPet("dragon", 3, 50)
+	pet.Exp = 50
+
+	// Hunger runs out 3h in, so the pet falls sick at 9h
+	advanceTo(t, clock, pet, clockStart.Add(12*time.Hour))
+	if pet.Health != PetSick || !pet.StarvingSince.Equal(clockStart.Add(3*time.Hour)) {
+		t.Fatalf("health %s, starving since %s; want sick, starving since 3h in", pet.Health, pet.StarvingSince)
+	}
+	if want := 50 - 3*clock.cfg.SickExpLoss; pet.Exp != want {
+		t.Fatalf("EXP %d after 3h sick, want %d", pet.Exp, want)
+	}
+	if len(db.transitions) != 1 {
+		t.Fatalf("recorded %d health transitions, want 1", len(db.transitions))
+	}
+}
+
+// testAway is away 1h-3h in, and 5h-6h in (cut short from 8h)
+func testAway(userID uuid.UUID) awayTime {
+	endedEarly := clockStart.Add(6 * time.Hour)
+	return awayTime{
+		{UserID: userID, StartsAt: clockStart.Add(time.Hour), EndsAt: clockStart.Add(3 * time.Hour)},
+		{UserID: userID, StartsAt: clockStart.Add(5 * time.Hour), EndsAt: clockStart.Add(8 * time.Hour), EndedAt: &endedEarly},
+	}
+}
+
+func TestAwayTimeActiveBetween(t *testing.T) {
+	away := testAway(uuid.New())
+	tests := []struct {
+		from, to time.Duration
+		active   time.Duration
+	}{
+		{0, 10 * time.Hour, 7 * time.Hour},
+		{2 * time.Hour, 4 * time.Hour, time.Hour},
+		{90 * time.Minute, 150 * time.Minute, 0},
+		{6 * time.Hour, 8 * time.Hour, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := away.activeBetween(clockStart.Add(tt.from), clockStart.Add(tt.to)); got != tt.active {
+			t.Errorf("activeBetween(%s, %s) = %s, want %s", tt.from, tt.to, got, tt.active)
+		}
+	}
+}
+
+func TestAwayTimeActiveAdd(t *testing.T) {
+	away := testAway(uuid.New())
+	tests := []struct {
+		from, d time.Duration
+		at      time.Duration
+	}{
+		{0, time.Hour, time.Hour},
+		{0, 2 * time.Hour, 4 * time.Hour},
+		{0, 4 * time.Hour, 7 * time.Hour},
+		// Starting while away, the clock starts when the vacation ends
+		{2 * time.Hour, 30 * time.Minute, 210 * time.Minute},
+		{7 * time.Hour, time.Hour, 8 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := away.activeAdd(clockStart.Add(tt.from), tt.d); !got.Equal(clockStart.Add(tt.at)) {
+			t.Errorf("activeAdd(%s, %s) = %s in, want %s", tt.from, tt.d, got.Sub(clockStart), tt.at)
+		}
+	}
+}
+
+func TestAwayTimeContains(t *testing.T) {
+	away := testAway(uuid.New())
+	for in, want := range map[time.Duration]bool{
+		0:                 false,
+		time.Hour:         true,
+		3 * time.Hour:     false,
+		330 * time.Minute: true,
+		6 * time.Hour:     false,
+		7 * time.Hour:     false,
+	} {
+		if got := away.contains(clockStart.Add(in)); got != want {
+			t.Errorf("contains(%s in) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestDecayPausesOnVacation(t *testing.T) {
+	db, clock := newClockTest()
+	pet := newClockPet("dragon", 50, 50)
+	db.vacations = append(db.vacations, testAway(pet.UserID)...)
+
+	// 4h pass, 2h of them away
+	advanceTo(t, clock, pet, clockStart.Add(4*time.Hour))
+	if pet.Hunger != 48 || !pet.DecayedAt.Equal(clockStart.Add(4*time.Hour)) {
+		t.Fatalf("hunger %d decayed up to %s in, want 48 up to 4h in", pet.Hunger, pet.DecayedAt.Sub(clockStart))
+	}
+}
+
+func TestStarvationPausesOnVacation(t *testing.T) {
+	_, clock := newClockTest()
+	pet := newClockPet("dragon", 0, 50)
+	starving := clockStart
+	pet.StarvingSince = &starving
+	away := awayTime{{UserID: pet.UserID, StartsAt: clockStart.Add(2 * time.Hour), EndsAt: clockStart.Add(10 * time.Hour)}}
+
+	if clock.updateHealth(pet, clockStart.Add(13*time.Hour), away); pet.Health != PetHealthy {
+		t.Fatalf("health %s after 5h of active starvation, want healthy", pet.Health)
+	}
+	transitions := clock.updateHealth(pet, clockStart.Add(15*time.Hour), away)
+	if len(transitions) != 1 || !transitions[0].CreatedAt.Equal(clockStart.Add(14*time.Hour)) {
+		t.Fatalf("got %d transitions, want sick at 14h in", len(transitions))
+	}
+}
+
+func TestInteractionCooldownAndDiminishingReturns(t *testing.T) {
+	_, clock := newClockTest()
+	clock.cfg.Cooldowns = map[string]time.Duration{PetActionPlay: 10 * time.Minute}
+	clock.cfg.InteractionWindow = time.Hour
+	clock.cfg.DiminishingFactor = 0.5
+	pet := newClockPet("dragon", 50, 50)
+
+	play := func(after time.Duration) (float64, error) {
+		return clock.interact(pet, PetActionPlay, clockStart.Add(after))
+	}
+
+	if m, err := play(0); err != nil || m != 1 {
+		t.Fatalf("first play: multiplier %v, %v; want 1", m, err)
+	}
+	if _, err := play(9 * time.Minute); err == nil {
+		t.Fatal("played again during the cooldown")
+	}
+	// The refused play doesn't count toward the window
+	if m, err := play(10 * time.Minute); err != nil || m != 0.5 {
+		t.Fatalf("second play: multiplier %v, %v; want 0.5", m, err)
+	}
+	if m, err := play(20 * time.Minute); err != nil || m != 0.25 {
+		t.Fatalf("third play: multiplier %v, %v; want 0.25", m, err)
+	}
+	// A new window restores the full boost
+	if m, err := play(time.Hour); err != nil || m != 1 {
+		t.Fatalf("play in a new window: multiplier %v, %v; want 1", m, err)
+	}
+
+	// Other actions have their own cooldown
+	if _, err := clock.interact(pet, PetActionFeed, clockStart.Add(time.Hour)); err != nil {
+		t.Fatalf("feeding after playing: %v", err)
+	}
+}
+
+func TestScaleBoost(t *testing.T) {
+	for _, tt := range []struct {
+		boost      int
+		multiplier float64
+		want       int
+	}{
+		{20, 1, 20},
+		{20, 0.5, 10},
+		{15, 0.25, 4},
+		{20, 1.1, 22},
+		{-5, 0.5, -5},
+	} {
+		if got := scaleBoost(tt.boost, tt.multiplier); got != tt.want {
+			t.Errorf("scaleBoost(%d, %v) = %d, want %d", tt.boost, tt.multiplier, got, tt.want)
+		}
+	}
+}
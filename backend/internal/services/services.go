@@ -2,15 +2,40 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"guildquest/internal/catalog"
+	"guildquest/internal/config"
+	"guildquest/internal/markdown"
 	"guildquest/internal/models"
 	"guildquest/internal/repositories"
+	"guildquest/internal/storage"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
 )
 
 type AuthService interface {
@@ -20,6 +45,7 @@ type AuthService interface {
 	GenerateTokens(userID uuid.UUID) (string, string, error)
 
 	GetUserByID(userID string) (*models.User, error)
+	SetTimeZone(userID uuid.UUID, timeZone string) (*models.User, error)
 }
 type authService struct {
 	userRepo  repositories.UserRepository
@@ -35,6 +61,19 @@ func (s *authService) GetUserByID(userID string) (*models.User, error) {
 	return s.userRepo.FindByID(id)
 }
 
+// SetTimeZone changes the zone the user's days are counted in
+func (s *authService) SetTimeZone(userID uuid.UUID, timeZone string) (*models.User, error) {
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+		return nil, errors.New("unknown time zone")
+	}
+
+	if err := s.userRepo.UpdateTimeZone(userID, timeZone); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(userID)
+}
+
 func NewAuthService(userRepo repositories.UserRepository, jwtSecret string) AuthService {
 	return &authService{userRepo: userRepo, jwtSecret: jwtSecret}
 }
@@ -143,31 +182,94 @@ func (s *authService) ValidateToken(tokenString string) (uuid.UUID, error) {
 
 // internal/services/task_service.go
 
+// defaultTaskStatuses seeds a user's board the first time it is used
+var defaultTaskStatuses = []models.TaskStatus{
+	{Name: "Backlog", Position: 0},
+	{Name: "In Progress", Position: 1},
+	{Name: "Review", Position: 2},
+	{Name: "Done", Position: 3, IsTerminal: true},
+}
+
 type TaskService interface {
 	CreateTask(userID uuid.UUID, req models.CreateTaskRequest) (*models.Task, error)
 	CreateBulkTasks(userID uuid.UUID, req models.BulkTaskRequest) ([]models.Task, error)
 	GetTasks(userID uuid.UUID) ([]models.Task, error)
-	CompleteTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error)
+	CompleteTask(userID uuid.UUID, taskID uuid.UUID) (*models.CompleteTaskResponse, error)
+	MoveTask(userID uuid.UUID, taskID uuid.UUID, req models.MoveTaskRequest) (*models.Task, error)
 	DeleteTask(userID uuid.UUID, taskID uuid.UUID) error
+
+	GetPendingReviews(reviewerID uuid.UUID) ([]models.Task, error)
+	ApproveTask(reviewerID uuid.UUID, taskID uuid.UUID, comment string) (*models.Task, error)
+	RejectTask(reviewerID uuid.UUID, taskID uuid.UUID, comment string) (*models.Task, error)
+
+	GetStatuses(userID uuid.UUID) ([]models.TaskStatus, error)
+	CreateStatus(userID uuid.UUID, req models.CreateTaskStatusRequest) (*models.TaskStatus, error)
+	UpdateStatus(userID uuid.UUID, statusID uuid.UUID, req models.UpdateTaskStatusRequest) (*models.TaskStatus, error)
+	DeleteStatus(userID uuid.UUID, statusID uuid.UUID) error
+
+	GetCategories() []catalog.Category
 }
 
 type taskService struct {
-	taskRepo repositories.TaskRepository
-	petRepo  repositories.PetRepository
-	userRepo repositories.UserRepository
+	taskRepo   repositories.TaskRepository
+	statusRepo repositories.TaskStatusRepository
+	petRepo    repositories.PetRepository
+	userRepo   repositories.UserRepository
+	ledgerRepo repositories.LedgerRepository
+	uow        repositories.UnitOfWork
+	store      storage.BlobStore
+	pets       petClock
+	loot       lootRoller
+	// Only set on copies bound to a unit of work; drops collects the loot
+	// their quests dropped
+	characterRepo repositories.CharacterRepository
+	streaks       streakTracker
+	achievements  achievementEngine
+	drops         []models.LootDrop
 }
 
-func NewTaskService(taskRepo repositories.TaskRepository, petRepo repositories.PetRepository, userRepo repositories.UserRepository) TaskService {
-	return &taskService{taskRepo: taskRepo, petRepo: petRepo, userRepo: userRepo}
+func NewTaskService(taskRepo repositories.TaskRepository, statusRepo repositories.TaskStatusRepository, petRepo repositories.PetRepository, userRepo repositories.UserRepository, ledgerRepo repositories.LedgerRepository, vacationRepo repositories.VacationRepository, uow repositories.UnitOfWork, store storage.BlobStore, petCfg PetConfig, rng *LootRNG) TaskService {
+	return &taskService{taskRepo: taskRepo, statusRepo: statusRepo, petRepo: petRepo, userRepo: userRepo, ledgerRepo: ledgerRepo, uow: uow, store: store, pets: petClock{petRepo: petRepo, vacationRepo: vacationRepo, cfg: petCfg}, loot: lootRoller{rng: rng}}
+}
+
+// in returns a copy of the service that works through repos
+func (s *taskService) in(repos repositories.Repositories) *taskService {
+	return &taskService{taskRepo: repos.Tasks, statusRepo: repos.Statuses, petRepo: repos.Pets, userRepo: repos.Users, ledgerRepo: repos.Ledger, uow: repos.Work, store: s.store, pets: s.pets.in(repos), loot: s.loot.in(repos), characterRepo: repos.Characters, streaks: streakTracker{repos: repos}, achievements: achievementEngine{repos: repos}}
 }
 
 func (s *taskService) CreateTask(userID uuid.UUID, req models.CreateTaskRequest) (*models.Task, error) {
+	status, rank, err := s.nextBacklogSlot(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptionHTML, err := markdown.Render(req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerID, err := s.resolveReviewer(userID, req.ReviewerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := normalizeCategory(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
-		UserID:      userID,
-		Title:       req.Title,
-		Description: req.Description,
-		Reward:      req.Reward,
-		Completed:   false,
+		UserID:           userID,
+		Title:            req.Title,
+		Description:      req.Description,
+		DescriptionHTML:  descriptionHTML,
+		Category:         category,
+		Reward:           req.Reward,
+		Completed:        false,
+		StatusID:         &status.ID,
+		Rank:             rank,
+		RequiresApproval: reviewerID != nil,
+		ReviewerID:       reviewerID,
 	}
 
 	if err := s.taskRepo.Create(task); err != nil {
@@ -178,14 +280,40 @@ func (s *taskService) CreateTask(userID uuid.UUID, req models.CreateTaskRequest)
 }
 
 func (s *taskService) CreateBulkTasks(userID uuid.UUID, req models.BulkTaskRequest) ([]models.Task, error) {
+	status, rank, err := s.nextBacklogSlot(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := make([]models.Task, len(req.Tasks))
 	for i, taskReq := range req.Tasks {
+		descriptionHTML, err := markdown.Render(taskReq.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewerID, err := s.resolveReviewer(userID, taskReq.ReviewerEmail)
+		if err != nil {
+			return nil, err
+		}
+
+		category, err := normalizeCategory(taskReq.Category)
+		if err != nil {
+			return nil, err
+		}
+
 		tasks[i] = models.Task{
-			UserID:      userID,
-			Title:       taskReq.Title,
-			Description: taskReq.Description,
-			Reward:      taskReq.Reward,
-			Completed:   false,
+			UserID:           userID,
+			Title:            taskReq.Title,
+			Description:      taskReq.Description,
+			DescriptionHTML:  descriptionHTML,
+			Category:         category,
+			Reward:           taskReq.Reward,
+			Completed:        false,
+			StatusID:         &status.ID,
+			Rank:             rank + float64(i),
+			RequiresApproval: reviewerID != nil,
+			ReviewerID:       reviewerID,
 		}
 	}
 
@@ -197,305 +325,4402 @@ func (s *taskService) CreateBulkTasks(userID uuid.UUID, req models.BulkTaskReque
 }
 
 func (s *taskService) GetTasks(userID uuid.UUID) ([]models.Task, error) {
+	// Make sure legacy tasks have a column before listing them
+	if _, err := s.ensureStatuses(userID); err != nil {
+		return nil, err
+	}
 	return s.taskRepo.FindByUserID(userID)
 }
 
-func (s *taskService) CompleteTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
-	task, err := s.taskRepo.FindByID(taskID)
+func (s *taskService) CompleteTask(userID uuid.UUID, taskID uuid.UUID) (*models.CompleteTaskResponse, error) {
+	var response *models.CompleteTaskResponse
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		tx := s.in(repos)
+		task, err := tx.completeTask(userID, taskID)
+		if err != nil {
+			return err
+		}
+		response = &models.CompleteTaskResponse{Task: *task, Drops: tx.drops}
+		if response.Drops == nil {
+			response.Drops = []models.LootDrop{}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("task not found")
+		return nil, err
 	}
+	return response, nil
+}
 
-	if task.UserID != userID {
-		return nil, errors.New("unauthorized")
+func (s *taskService) completeTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	task, err := s.findOwnedTask(userID, taskID)
+	if err != nil {
+		return nil, err
 	}
 
 	if task.Completed {
 		return nil, errors.New("task already completed")
 	}
 
-	// Mark task as complete
-	if err := s.taskRepo.MarkComplete(taskID); err != nil {
+	statuses, err := s.ensureStatuses(userID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Add gold to user
-	currentGold, err := s.userRepo.GetGold(userID)
+	done := terminalStatus(statuses)
+	rank, err := s.taskRepo.MaxRank(done.ID)
 	if err != nil {
 		return nil, err
 	}
-	if err := s.userRepo.UpdateGold(userID, currentGold+task.Reward); err != nil {
-		return nil, err
-	}
-
-	// Update pet: +10 EXP, +5 happiness
-	pet, err := s.petRepo.FindByUserID(userID)
-	if err == nil {
-		pet.Exp += 10
-		pet.Happiness = clamp(pet.Happiness+5, 0, 100)
 
-		// Check for level up
-		requiredExp := pet.Level * 100
-		if pet.Exp >= requiredExp {
-			pet.Level++
-			pet.Exp = 0
-		}
+	return s.applyMove(task, done, rank+1)
+}
 
-		s.petRepo.Update(pet)
+func (s *taskService) MoveTask(userID uuid.UUID, taskID uuid.UUID, req models.MoveTaskRequest) (*models.Task, error) {
+	var task *models.Task
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		task, err = s.in(repos).moveTask(userID, taskID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	task.Completed = true
 	return task, nil
 }
 
-func (s *taskService) DeleteTask(userID uuid.UUID, taskID uuid.UUID) error {
-	task, err := s.taskRepo.FindByID(taskID)
+func (s *taskService) moveTask(userID uuid.UUID, taskID uuid.UUID, req models.MoveTaskRequest) (*models.Task, error) {
+	task, err := s.findOwnedTask(userID, taskID)
 	if err != nil {
-		return errors.New("task not found")
+		return nil, err
 	}
 
-	if task.UserID != userID {
-		return errors.New("unauthorized")
+	statuses, err := s.ensureStatuses(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.taskRepo.Delete(taskID)
-}
-
-// internal/services/pet_service.go
+	target := findStatus(statuses, req.StatusID)
+	if target == nil {
+		return nil, errors.New("status not found")
+	}
 
-type PetService interface {
-	GetPet(userID uuid.UUID) (*models.Pet, error)
-	FeedPet(userID uuid.UUID) (*models.Pet, error)
-	PlayWithPet(userID uuid.UUID) (*models.Pet, error)
-	CreatePet(userID uuid.UUID) (*models.Pet, error)
-}
+	rank, err := s.rankBetween(target.ID, task.ID, req.BeforeID, req.AfterID)
+	if err != nil {
+		return nil, err
+	}
 
-type petService struct {
-	petRepo  repositories.PetRepository
-	userRepo repositories.UserRepository
+	return s.applyMove(task, target, rank)
 }
 
-func NewPetService(petRepo repositories.PetRepository, userRepo repositories.UserRepository) PetService {
-	return &petService{petRepo: petRepo, userRepo: userRepo}
-}
+func (s *taskService) DeleteTask(userID uuid.UUID, taskID uuid.UUID) error {
+	if _, err := s.findOwnedTask(userID, taskID); err != nil {
+		return err
+	}
 
-func (s *petService) GetPet(userID uuid.UUID) (*models.Pet, error) {
-	pet, err := s.petRepo.FindByUserID(userID)
+	var attachments []models.Attachment
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		if attachments, err = repos.Attachments.DeleteByTaskID(taskID); err != nil {
+			return err
+		}
+		// A timer left running would block new ones and could still pay focus EXP
+		if err := repos.TimeEntries.StopByTaskID(taskID, time.Now()); err != nil {
+			return err
+		}
+		return repos.Tasks.Delete(taskID)
+	})
 	if err != nil {
-		// Create pet if doesn't exist
-		return s.CreatePet(userID)
+		return err
 	}
-	return pet, nil
-}
 
-func (s *petService) CreatePet(userID uuid.UUID) (*models.Pet, error) {
-	pet := &models.Pet{
-		UserID:    userID,
-		Type:      "dragon",
-		Level:     1,
-		Exp:       0,
-		Hunger:    100,
-		Happiness: 100,
+	// Blobs go once nothing points at them
+	for i := range attachments {
+		deleteBlobs(s.store, &attachments[i])
 	}
+	return nil
+}
 
-	if err := s.petRepo.Create(pet); err != nil {
+func (s *taskService) GetPendingReviews(reviewerID uuid.UUID) ([]models.Task, error) {
+	return s.taskRepo.FindPendingReview(reviewerID)
+}
+
+func (s *taskService) ApproveTask(reviewerID uuid.UUID, taskID uuid.UUID, comment string) (*models.Task, error) {
+	var task *models.Task
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		task, err = s.in(repos).approveTask(reviewerID, taskID, comment)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return pet, nil
+	return task, nil
 }
 
-func (s *petService) FeedPet(userID uuid.UUID) (*models.Pet, error) {
-	// Check gold
-	gold, err := s.userRepo.GetGold(userID)
+func (s *taskService) approveTask(reviewerID uuid.UUID, taskID uuid.UUID, comment string) (*models.Task, error) {
+	task, err := s.findTaskForReview(reviewerID, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	if gold < 20 {
-		return nil, errors.New("insufficient gold")
+	now := time.Now()
+	task.ReviewStatus = models.ReviewApproved
+	task.ReviewComment = comment
+	task.ReviewedAt = &now
+
+	// Approval completes the task on the owner's board and pays out
+	statuses, err := s.ensureStatuses(task.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Deduct gold
-	if err := s.userRepo.UpdateGold(userID, gold-20); err != nil {
+	done := terminalStatus(statuses)
+	rank, err := s.taskRepo.MaxRank(done.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Update pet
-	pet, err := s.petRepo.FindByUserID(userID)
+	return s.applyMove(task, done, rank+1)
+}
+
+func (s *taskService) RejectTask(reviewerID uuid.UUID, taskID uuid.UUID, comment string) (*models.Task, error) {
+	task, err := s.findTaskForReview(reviewerID, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	pet.Hunger = clamp(pet.Hunger+30, 0, 100)
-	pet.Happiness = clamp(pet.Happiness+10, 0, 100)
+	now := time.Now()
+	task.ReviewStatus = models.ReviewRejected
+	task.ReviewComment = comment
+	task.ReviewedAt = &now
 
-	if err := s.petRepo.Update(pet); err != nil {
+	if err := s.taskRepo.Update(task); err != nil {
 		return nil, err
 	}
 
-	return pet, nil
+	return task, nil
 }
 
-func (s *petService) PlayWithPet(userID uuid.UUID) (*models.Pet, error) {
-	pet, err := s.petRepo.FindByUserID(userID)
+func (s *taskService) GetStatuses(userID uuid.UUID) ([]models.TaskStatus, error) {
+	return s.ensureStatuses(userID)
+}
+
+func (s *taskService) CreateStatus(userID uuid.UUID, req models.CreateTaskStatusRequest) (*models.TaskStatus, error) {
+	statuses, err := s.ensureStatuses(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	pet.Happiness = clamp(pet.Happiness+20, 0, 100)
-	pet.Hunger = clamp(pet.Hunger-5, 0, 100)
+	status := &models.TaskStatus{
+		UserID:     userID,
+		Name:       req.Name,
+		Position:   len(statuses),
+		IsTerminal: req.IsTerminal,
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
 
-	if err := s.petRepo.Update(pet); err != nil {
+	if err := s.statusRepo.Create(status); err != nil {
 		return nil, err
 	}
 
-	return pet, nil
+	return status, nil
 }
 
-// internal/services/decoration_service.go
+func (s *taskService) UpdateStatus(userID uuid.UUID, statusID uuid.UUID, req models.UpdateTaskStatusRequest) (*models.TaskStatus, error) {
+	statuses, err := s.ensureStatuses(userID)
+	if err != nil {
+		return nil, err
+	}
 
-type DecorationService interface {
-	GetDecorations(userID uuid.UUID) ([]models.Decoration, error)
-	BuyDecoration(userID uuid.UUID, decoration string) error
-}
+	status := findStatus(statuses, statusID)
+	if status == nil {
+		return nil, errors.New("status not found")
+	}
 
-type decorationService struct {
-	decorationRepo repositories.DecorationRepository
-	userRepo       repositories.UserRepository
-}
+	if req.Name != nil {
+		status.Name = *req.Name
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.IsTerminal != nil && *req.IsTerminal != status.IsTerminal {
+		// Flipping a column would silently complete or reopen its tasks
+		count, err := s.taskRepo.CountByStatusID(status.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, errors.New("status column is not empty")
+		}
 
-func NewDecorationService(decorationRepo repositories.DecorationRepository, userRepo repositories.UserRepository) DecorationService {
-	return &decorationService{decorationRepo: decorationRepo, userRepo: userRepo}
-}
+		status.IsTerminal = *req.IsTerminal
+		if !hasBothStatusKinds(statuses) {
+			return nil, errors.New("board needs at least one open and one done status")
+		}
+	}
 
-func (s *decorationService) GetDecorations(userID uuid.UUID) ([]models.Decoration, error) {
-	return s.decorationRepo.FindByUserID(userID)
+	if err := s.statusRepo.Update(status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
 }
 
-func (s *decorationService) BuyDecoration(userID uuid.UUID, decoration string) error {
-	// Check if already owned
-	exists, err := s.decorationRepo.Exists(userID, decoration)
+func (s *taskService) DeleteStatus(userID uuid.UUID, statusID uuid.UUID) error {
+	statuses, err := s.ensureStatuses(userID)
 	if err != nil {
 		return err
 	}
-	if exists {
-		return errors.New("decoration already owned")
-	}
 
-	// Check gold
-	gold, err := s.userRepo.GetGold(userID)
-	if err != nil {
-		return err
+	var remaining []models.TaskStatus
+	for _, st := range statuses {
+		if st.ID != statusID {
+			remaining = append(remaining, st)
+		}
 	}
-	if gold < 50 {
-		return errors.New("insufficient gold")
+	if len(remaining) == len(statuses) {
+		return errors.New("status not found")
 	}
 
-	// Deduct gold
-	if err := s.userRepo.UpdateGold(userID, gold-50); err != nil {
-		return err
+	if !hasBothStatusKinds(remaining) {
+		return errors.New("board needs at least one open and one done status")
 	}
 
-	// Create decoration
-	dec := &models.Decoration{
-		UserID:     userID,
-		Decoration: decoration,
+	count, err := s.taskRepo.CountByStatusID(statusID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("status column is not empty")
 	}
 
-	return s.decorationRepo.Create(dec)
+	return s.statusRepo.Delete(statusID)
 }
 
-// internal/services/sync_service.go
-
-type SyncService interface {
-	Sync(userID uuid.UUID, lastSyncAt time.Time) (*models.SyncResponse, error)
-}
+func (s *taskService) findOwnedTask(userID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
 
-type syncService struct {
-	taskRepo       repositories.TaskRepository
-	petRepo        repositories.PetRepository
-	decorationRepo repositories.DecorationRepository
-}
+	if task.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
 
-func NewSyncService(taskRepo repositories.TaskRepository, petRepo repositories.PetRepository, decorationRepo repositories.DecorationRepository) SyncService {
-	return &syncService{taskRepo: taskRepo, petRepo: petRepo, decorationRepo: decorationRepo}
+	return task, nil
 }
 
-func (s *syncService) Sync(userID uuid.UUID, lastSyncAt time.Time) (*models.SyncResponse, error) {
-	tasks, err := s.taskRepo.FindUpdatedSince(userID, lastSyncAt)
+func (s *taskService) findTaskForReview(reviewerID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("task not found")
 	}
 
-	pet, err := s.petRepo.FindByUserID(userID)
-	if err != nil {
-		pet = nil
+	if task.ReviewerID == nil || *task.ReviewerID != reviewerID {
+		return nil, errors.New("unauthorized")
 	}
 
-	decorations, err := s.decorationRepo.FindUpdatedSince(userID, lastSyncAt)
-	if err != nil {
-		return nil, err
+	if task.ReviewStatus != models.ReviewPending {
+		return nil, errors.New("task is not awaiting review")
 	}
 
-	return &models.SyncResponse{
-		Tasks:       tasks,
-		Pet:         pet,
-		Decorations: decorations,
-		SyncedAt:    time.Now(),
-	}, nil
+	return task, nil
 }
 
-// internal/services/invite_service.go
+// resolveReviewer looks up the designated reviewer, if any
+func (s *taskService) resolveReviewer(userID uuid.UUID, email string) (*uuid.UUID, error) {
+	if email == "" {
+		return nil, nil
+	}
 
-type InviteService interface {
-	CreateInvite(email string) (*models.InviteResponse, error)
-	ValidateInvite(token string) (string, error)
-}
+	reviewer, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, errors.New("reviewer not found")
+	}
 
-type inviteService struct {
-	jwtSecret string
-	appURL    string
-}
+	if reviewer.ID == userID {
+		return nil, errors.New("you cannot review your own task")
+	}
 
-func NewInviteService(jwtSecret, appURL string) InviteService {
-	return &inviteService{jwtSecret: jwtSecret, appURL: appURL}
+	return &reviewer.ID, nil
 }
 
-func (s *inviteService) CreateInvite(email string) (*models.InviteResponse, error) {
-	claims := jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(time.Hour * 24 * 7).Unix(),
-		"type":  "invite",
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+// ensureStatuses returns the user's board columns, seeding the defaults
+// (and placing any pre-existing tasks) on first use.
+func (s *taskService) ensureStatuses(userID uuid.UUID) ([]models.TaskStatus, error) {
+	statuses, err := s.statusRepo.FindByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
+	if len(statuses) > 0 {
+		return statuses, nil
+	}
 
-	inviteURL := s.appURL + "/invite/" + tokenString
-	qrCodeURL := s.appURL + "/api/v1/invite/" + tokenString + "/qr"
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		// Checked again under the user's lock, so two first requests
+		// don't both seed a board
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+		var err error
+		statuses, err = repos.Statuses.FindByUserID(userID)
+		if err != nil || len(statuses) > 0 {
+			return err
+		}
 
-	return &models.InviteResponse{
-		Token:     tokenString,
-		QRCodeURL: qrCodeURL,
-		InviteURL: inviteURL,
-	}, nil
-}
+		statuses = make([]models.TaskStatus, len(defaultTaskStatuses))
+		for i, st := range defaultTaskStatuses {
+			st.UserID = userID
+			statuses[i] = st
+		}
 
-func (s *inviteService) ValidateInvite(token string) (string, error) {
-	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
+		if err := repos.Statuses.CreateBulk(statuses); err != nil {
+			return err
+		}
+		return repos.Tasks.AssignMissingStatus(userID, openStatus(statuses).ID, terminalStatus(statuses).ID)
 	})
-
-	if err != nil || !parsedToken.Valid {
-		return "", errors.New("invalid invite token")
+	if err != nil {
+		return nil, err
 	}
 
-	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", errors.New("invalid token claims")
+	return statuses, nil
+}
+
+// nextBacklogSlot returns the first open column and a rank at its bottom
+func (s *taskService) nextBacklogSlot(userID uuid.UUID) (*models.TaskStatus, float64, error) {
+	statuses, err := s.ensureStatuses(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	status := openStatus(statuses)
+	rank, err := s.taskRepo.MaxRank(status.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return status, rank + 1, nil
+}
+
+// rankBetween computes a fractional rank that places a task between
+// beforeID (the task above it) and afterID (the task below it).
+func (s *taskService) rankBetween(statusID, taskID uuid.UUID, beforeID, afterID *uuid.UUID) (float64, error) {
+	all, err := s.taskRepo.FindByStatusID(statusID)
+	if err != nil {
+		return 0, err
+	}
+
+	column := make([]models.Task, 0, len(all))
+	for _, t := range all {
+		if t.ID != taskID {
+			column = append(column, t)
+		}
+	}
+
+	// Resolve the slot as an index into column: the task lands before column[slot]
+	slot := len(column)
+	if beforeID != nil {
+		idx := indexOfTask(column, *beforeID)
+		if idx < 0 {
+			return 0, errors.New("invalid position")
+		}
+		slot = idx + 1
+	}
+	if afterID != nil {
+		idx := indexOfTask(column, *afterID)
+		if idx < 0 || (beforeID != nil && idx != slot) {
+			return 0, errors.New("invalid position")
+		}
+		slot = idx
+	}
+
+	rank, ok := midRank(column, slot)
+	if ok {
+		return rank, nil
+	}
+
+	// Float precision is exhausted between the neighbours: respace the column
+	for i := range column {
+		column[i].Rank = float64(i + 1)
+		if err := s.taskRepo.Update(&column[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	rank, _ = midRank(column, slot)
+	return rank, nil
+}
+
+// applyMove puts the task into a column. Rewards fire only the first time
+// a task enters a terminal column; tasks that need approval are submitted
+// for review instead and stay where they are.
+func (s *taskService) applyMove(task *models.Task, status *models.TaskStatus, rank float64) (*models.Task, error) {
+	now := time.Now()
+
+	enteringDone := status.IsTerminal && !task.Completed
+	if enteringDone && task.RequiresApproval && task.ReviewStatus != models.ReviewApproved {
+		return s.submitForReview(task)
+	}
+
+	task.StatusID = &status.ID
+	task.Rank = rank
+
+	if enteringDone {
+		task.Completed = true
+		task.CompletedAt = &now
+	}
+	if !status.IsTerminal && task.Completed {
+		task.Completed = false
+		task.CompletedAt = nil
+	}
+
+	reward := enteringDone && task.RewardedAt == nil
+	if reward {
+		task.RewardedAt = &now
+	}
+
+	if err := s.taskRepo.Update(task); err != nil {
+		return nil, err
+	}
+
+	if reward {
+		if err := s.grantRewards(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+func (s *taskService) submitForReview(task *models.Task) (*models.Task, error) {
+	if task.ReviewStatus == models.ReviewPending {
+		return nil, errors.New("task is already awaiting review")
+	}
+
+	task.ReviewStatus = models.ReviewPending
+	task.ReviewComment = ""
+	task.ReviewedAt = nil
+
+	if err := s.taskRepo.Update(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *taskService) grantRewards(task *models.Task) error {
+	// Keyed by task, so a task pays out once even if completions race
+	key := "quest:" + task.ID.String()
+	applied, err := s.ledgerRepo.Apply(&models.GoldTransaction{
+		UserID:         task.UserID,
+		Amount:         task.Reward,
+		Reason:         models.GoldQuestReward,
+		RefType:        "task",
+		RefID:          task.ID.String(),
+		IdempotencyKey: &key,
+	})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return nil
+	}
+
+	// The character's attributes, as they were before this quest, boost
+	// its rewards
+	character, err := s.characterRepo.Lock(task.UserID)
+	if err != nil {
+		return err
+	}
+	bonuses := characterBonuses(character)
+
+	if gold := task.Reward * bonuses.Gold / 100; gold > 0 {
+		key := "bonus:" + task.ID.String()
+		if _, err := s.ledgerRepo.Apply(&models.GoldTransaction{
+			UserID:         task.UserID,
+			Amount:         gold,
+			Reason:         models.GoldAttributeBonus,
+			RefType:        "task",
+			RefID:          task.ID.String(),
+			IdempotencyKey: &key,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Update pet: EXP scaled by the reward, +5 happiness
+	pet, err := s.pets.find(task.UserID)
+	if err == nil {
+		now := time.Now()
+		pet.LastQuestAt = &now
+
+		if pet.Health == PetFainted {
+			// Quests completed while fainted count toward revival instead
+			pet.RevivalProgress++
+		} else {
+			recordPetEvent(pet, PetEventQuest, fmt.Sprintf("Cheered as you completed %q", task.Title))
+			exp := s.pets.cfg.Progression.QuestExp(task.Reward)
+			s.pets.cfg.Progression.AddExp(pet, scaleBoost(exp, 1+float64(bonuses.PetExp)/100))
+			pet.Happiness = clamp(pet.Happiness+scaleBoost(5, 1+float64(bonuses.PetHappiness)/100), 0, 100)
+
+			// Categorized quests steer how the pet evolves
+			if task.Category != "" {
+				if err := s.petRepo.IncrementQuestTally(pet.ID, task.Category); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := s.pets.save(pet, now); err != nil {
+			return err
+		}
+		if pet.Health != PetFainted {
+			if err := s.achievements.publish(task.UserID, EventPetProgressed); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.streaks.record(task.UserID, time.Now()); err != nil {
+		return err
+	}
+
+	drop, err := s.loot.roll(task, bonuses.Loot)
+	if err != nil {
+		return err
+	}
+	if drop != nil {
+		s.drops = append(s.drops, *drop)
+	}
+
+	advanceCharacter(character, task)
+	if err := s.characterRepo.Save(character); err != nil {
+		return err
+	}
+
+	return s.achievements.publish(task.UserID, EventQuestCompleted)
+}
+
+func (s *taskService) GetCategories() []catalog.Category {
+	return catalog.Categories()
+}
+
+// normalizeCategory validates an optional task category against the catalog
+func normalizeCategory(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category != "" && !catalog.IsCategory(category) {
+		return "", errors.New("unknown task category")
+	}
+	return category, nil
+}
+
+func findStatus(statuses []models.TaskStatus, id uuid.UUID) *models.TaskStatus {
+	for i := range statuses {
+		if statuses[i].ID == id {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func openStatus(statuses []models.TaskStatus) *models.TaskStatus {
+	for i := range statuses {
+		if !statuses[i].IsTerminal {
+			return &statuses[i]
+		}
+	}
+	return &statuses[0]
+}
+
+func terminalStatus(statuses []models.TaskStatus) *models.TaskStatus {
+	for i := range statuses {
+		if statuses[i].IsTerminal {
+			return &statuses[i]
+		}
+	}
+	return &statuses[len(statuses)-1]
+}
+
+func hasBothStatusKinds(statuses []models.TaskStatus) bool {
+	var open, done bool
+	for _, st := range statuses {
+		if st.IsTerminal {
+			done = true
+		} else {
+			open = true
+		}
+	}
+	return open && done
+}
+
+func indexOfTask(tasks []models.Task, id uuid.UUID) int {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// midRank returns a rank strictly between column[slot-1] and column[slot],
+// or false when the two neighbours are too close to split.
+func midRank(column []models.Task, slot int) (float64, bool) {
+	switch {
+	case len(column) == 0:
+		return 1, true
+	case slot == 0:
+		return column[0].Rank - 1, true
+	case slot == len(column):
+		return column[slot-1].Rank + 1, true
+	}
+
+	lo, hi := column[slot-1].Rank, column[slot].Rank
+	mid := lo + (hi-lo)/2
+	return mid, mid > lo && mid < hi
+}
+
+// internal/services/pet_service.go
+
+type PetService interface {
+	GetPet(userID uuid.UUID) (*models.Pet, error)
+	FeedPet(userID uuid.UUID) (*models.Pet, error)
+	PlayWithPet(userID uuid.UUID) (*models.Pet, error)
+	CreatePet(userID uuid.UUID) (*models.Pet, error)
+	GetHealth(userID uuid.UUID) (*models.PetHealthResponse, error)
+	RevivePet(userID uuid.UUID, method string, idempotencyKey string) (*models.Pet, error)
+	DecayAll() (int, error)
+
+	GetPets(userID uuid.UUID) ([]models.Pet, error)
+	GetSpecies() []catalog.Species
+	AdoptPet(userID uuid.UUID, req models.AdoptPetRequest, idempotencyKey string) (*models.Pet, error)
+	ActivatePet(userID uuid.UUID, petID uuid.UUID) (*models.Pet, error)
+	RenamePet(userID uuid.UUID, petID uuid.UUID, name string) (*models.Pet, error)
+	GetEvolution(userID uuid.UUID, petID uuid.UUID) (*models.PetEvolutionResponse, error)
+	UseItem(userID uuid.UUID, itemID string) (*models.UseItemResponse, error)
+	GetEvents(userID uuid.UUID, limit int) ([]models.PetEvent, error)
+	EquipAccessory(userID uuid.UUID, petID uuid.UUID, accessoryID string) (*models.Pet, error)
+	UnequipAccessory(userID uuid.UUID, petID uuid.UUID, slot string) (*models.Pet, error)
+}
+
+const maxPetsPerUser = 6
+
+// defaultFoodItem is what FeedPet serves
+const defaultFoodItem = "kibble"
+
+type petService struct {
+	petRepo        repositories.PetRepository
+	ledgerRepo     repositories.LedgerRepository
+	taskRepo       repositories.TaskRepository
+	inventoryRepo  repositories.InventoryRepository
+	decorationRepo repositories.DecorationRepository
+	uow            repositories.UnitOfWork
+	pets           petClock
+	// achievements is only set on copies bound to a unit of work
+	achievements achievementEngine
+}
+
+func NewPetService(petRepo repositories.PetRepository, ledgerRepo repositories.LedgerRepository, taskRepo repositories.TaskRepository, inventoryRepo repositories.InventoryRepository, decorationRepo repositories.DecorationRepository, vacationRepo repositories.VacationRepository, uow repositories.UnitOfWork, petCfg PetConfig) PetService {
+	return &petService{petRepo: petRepo, ledgerRepo: ledgerRepo, taskRepo: taskRepo, inventoryRepo: inventoryRepo, decorationRepo: decorationRepo, uow: uow, pets: petClock{petRepo: petRepo, vacationRepo: vacationRepo, cfg: petCfg}}
+}
+
+// in returns a copy of the service that works through repos
+func (s *petService) in(repos repositories.Repositories) *petService {
+	return &petService{petRepo: repos.Pets, ledgerRepo: repos.Ledger, taskRepo: repos.Tasks, inventoryRepo: repos.Inventory, decorationRepo: repos.Decorations, uow: repos.Work, pets: s.pets.in(repos), achievements: achievementEngine{repos: repos}}
+}
+
+// GetPet returns the active pet, hatching a starter pet for new players
+func (s *petService) GetPet(userID uuid.UUID) (*models.Pet, error) {
+	pet, err := s.pets.find(userID)
+	if err == nil {
+		return pet, nil
+	}
+
+	// Owns pets but none is active: promote the oldest
+	pets, err := s.petRepo.FindAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pets) > 0 {
+		return s.ActivatePet(userID, pets[0].ID)
+	}
+
+	return s.CreatePet(userID)
+}
+
+func (s *petService) CreatePet(userID uuid.UUID) (*models.Pet, error) {
+	var result *models.Pet
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		result, err = s.in(repos).createPet(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *petService) createPet(userID uuid.UUID) (*models.Pet, error) {
+	pet := newPet(userID, catalog.StarterSpecies(), "")
+	pet.Active = true
+
+	if err := s.petRepo.Create(pet); err != nil {
+		return nil, err
+	}
+
+	recordPetEvent(pet, PetEventHatched, "Hatched from its egg")
+	if err := s.pets.flushEvents(pet, nil); err != nil {
+		return nil, err
+	}
+
+	return pet, nil
+}
+
+func (s *petService) GetEvents(userID uuid.UUID, limit int) ([]models.PetEvent, error) {
+	pet, err := s.GetPet(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.petRepo.FindEvents(pet.ID, limit)
+}
+
+func (s *petService) GetPets(userID uuid.UUID) ([]models.Pet, error) {
+	if _, err := s.GetPet(userID); err != nil {
+		return nil, err
+	}
+	return s.pets.findAll(userID)
+}
+
+func (s *petService) GetSpecies() []catalog.Species {
+	return catalog.AllSpecies()
+}
+
+func (s *petService) AdoptPet(userID uuid.UUID, req models.AdoptPetRequest, idempotencyKey string) (*models.Pet, error) {
+	var result *models.Pet
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		// Taken before the pet count and species checks, so concurrent
+		// adoptions can't both pass them
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+		var err error
+		result, err = s.in(repos).adoptPet(userID, req, idempotencyKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *petService) adoptPet(userID uuid.UUID, req models.AdoptPetRequest, idempotencyKey string) (*models.Pet, error) {
+	species, ok := catalog.FindSpecies(req.Species)
+	if !ok {
+		return nil, errors.New("species not found")
+	}
+
+	pets, err := s.GetPets(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pets) >= maxPetsPerUser {
+		return nil, fmt.Errorf("you can own at most %d pets", maxPetsPerUser)
+	}
+	for _, p := range pets {
+		if p.Type == species.ID {
+			return nil, errors.New("you already own this species")
+		}
+	}
+
+	switch req.Method {
+	case "gold":
+		if species.Adoption.GoldCost <= 0 {
+			return nil, errors.New("this species cannot be bought")
+		}
+		entry := &models.GoldTransaction{
+			UserID:  userID,
+			Amount:  -species.Adoption.GoldCost,
+			Reason:  models.GoldPetAdoption,
+			RefType: "species",
+			RefID:   species.ID,
+		}
+		if idempotencyKey != "" {
+			key := "adopt:" + species.ID + ":" + idempotencyKey
+			entry.IdempotencyKey = &key
+		}
+		applied, err := s.ledgerRepo.Apply(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			return nil, errors.New("this adoption was already made")
+		}
+	case "milestone":
+		if err := s.checkAdoptionMilestones(userID, species.Adoption); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid adoption method")
+	}
+
+	pet := newPet(userID, species, req.Name)
+	if err := s.petRepo.Create(pet); err != nil {
+		return nil, err
+	}
+
+	recordPetEvent(pet, PetEventHatched, "Joined your guild")
+	if err := s.pets.flushEvents(pet, nil); err != nil {
+		return nil, err
+	}
+
+	return pet, nil
+}
+
+func (s *petService) ActivatePet(userID uuid.UUID, petID uuid.UUID) (*models.Pet, error) {
+	pet, err := s.findOwnedPet(userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.petRepo.SetActive(userID, petID); err != nil {
+		return nil, err
+	}
+
+	pet.Active = true
+	return pet, nil
+}
+
+func (s *petService) RenamePet(userID uuid.UUID, petID uuid.UUID, name string) (*models.Pet, error) {
+	pet, err := s.findOwnedPet(userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	pet.Name = strings.TrimSpace(name)
+	if pet.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if err := s.petRepo.Update(pet); err != nil {
+		return nil, err
+	}
+
+	return pet, nil
+}
+
+func (s *petService) GetEvolution(userID uuid.UUID, petID uuid.UUID) (*models.PetEvolutionResponse, error) {
+	pet, err := s.findOwnedPet(userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	tallies, err := s.petRepo.FindQuestTallies(pet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	evolutions, err := s.petRepo.FindEvolutions(pet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PetEvolutionResponse{
+		Form:       pet.Form,
+		FormName:   catalog.FormName(pet.Type, pet.Form),
+		Tallies:    tallies,
+		Evolutions: evolutions,
+	}
+
+	if next, ok := catalog.NextEvolution(pet.Type, pet.Form); ok {
+		response.NextLevel = next.Level
+		for _, b := range next.Branches {
+			response.NextForms = append(response.NextForms, b.Form)
+		}
+	}
+
+	return response, nil
+}
+
+func (s *petService) EquipAccessory(userID uuid.UUID, petID uuid.UUID, accessoryID string) (*models.Pet, error) {
+	accessory, ok := catalog.FindAccessory(accessoryID)
+	if !ok {
+		return nil, errors.New("accessory not found")
+	}
+
+	pet, err := s.findOwnedPet(userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	owned, err := s.decorationRepo.Exists(userID, accessory.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, errors.New("you don't own this accessory")
+	}
+
+	if !accessory.Fits(pet.Type) {
+		return nil, fmt.Errorf("%s doesn't fit a %s", accessory.Name, catalog.SpeciesOrDefault(pet.Type).Name)
+	}
+
+	if err := s.petRepo.Equip(&models.PetEquipment{
+		PetID:       pet.ID,
+		Slot:        accessory.Slot,
+		UserID:      userID,
+		AccessoryID: accessory.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return pet, s.pets.annotate(pet, time.Now())
+}
+
+func (s *petService) UnequipAccessory(userID uuid.UUID, petID uuid.UUID, slot string) (*models.Pet, error) {
+	if !catalog.IsSlot(slot) {
+		return nil, errors.New("unknown slot")
+	}
+
+	pet, err := s.findOwnedPet(userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.petRepo.Unequip(pet.ID, slot)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, errors.New("nothing equipped in this slot")
+	}
+
+	return pet, s.pets.annotate(pet, time.Now())
+}
+
+func (s *petService) findOwnedPet(userID uuid.UUID, petID uuid.UUID) (*models.Pet, error) {
+	pet, err := s.pets.findByID(petID)
+	if err != nil {
+		return nil, errors.New("pet not found")
+	}
+
+	if pet.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return pet, nil
+}
+
+// checkAdoptionMilestones verifies the free adoption requirements
+func (s *petService) checkAdoptionMilestones(userID uuid.UUID, adoption catalog.Adoption) error {
+	if adoption.MinCompletedTasks == 0 && adoption.MinPetLevel == 0 {
+		return errors.New("this species has no adoption milestones")
+	}
+
+	if adoption.MinCompletedTasks > 0 {
+		completed, err := s.taskRepo.CountCompleted(userID)
+		if err != nil {
+			return err
+		}
+		if completed < int64(adoption.MinCompletedTasks) {
+			return fmt.Errorf("complete %d quests to adopt this species", adoption.MinCompletedTasks)
+		}
+	}
+
+	if adoption.MinPetLevel > 0 {
+		level, err := s.petRepo.MaxLevelByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if level < adoption.MinPetLevel {
+			return fmt.Errorf("raise a pet to level %d to adopt this species", adoption.MinPetLevel)
+		}
+	}
+
+	return nil
+}
+
+func newPet(userID uuid.UUID, species catalog.Species, name string) *models.Pet {
+	now := time.Now()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = species.Name
+	}
+
+	return &models.Pet{
+		UserID:    userID,
+		Name:      name,
+		Type:      species.ID,
+		Level:     1,
+		Exp:       0,
+		Hunger:    100,
+		Happiness: 100,
+		Health:    PetHealthy,
+		DecayedAt: &now,
+	}
+}
+
+// FeedPet feeds the pet its everyday food, buying one first if the user
+// has none in their inventory
+func (s *petService) FeedPet(userID uuid.UUID) (*models.Pet, error) {
+	var result *models.Pet
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		result, err = s.in(repos).feedPet(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *petService) feedPet(userID uuid.UUID) (*models.Pet, error) {
+	food, ok := catalog.FindItem(defaultFoodItem)
+	if !ok {
+		return nil, errors.New("item not found")
+	}
+
+	pet, err := s.pets.find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pet.Health == PetFainted {
+		return nil, errors.New("pet has fainted")
+	}
+
+	owned, err := s.inventoryRepo.Quantity(userID, food.ID)
+	if err != nil {
+		return nil, err
+	}
+	if owned == 0 {
+		if err := buyItem(s.ledgerRepo, s.inventoryRepo, userID, food, 1, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.useItem(pet, food)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Pet, nil
+}
+
+// PlayWithPet plays with the most fun toy the user owns that is off
+// cooldown, or just plays without one
+func (s *petService) PlayWithPet(userID uuid.UUID) (*models.Pet, error) {
+	var result *models.Pet
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		result, err = s.in(repos).playWithPet(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *petService) playWithPet(userID uuid.UUID) (*models.Pet, error) {
+	pet, err := s.pets.find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pet.Health == PetFainted {
+		return nil, errors.New("pet has fainted")
+	}
+
+	toy, err := s.bestToy(pet)
+	if err != nil {
+		return nil, err
+	}
+	if toy != nil {
+		result, err := s.useItem(pet, *toy)
+		if err != nil {
+			return nil, err
+		}
+		return result.Pet, nil
+	}
+
+	now := time.Now()
+	multiplier, err := s.pets.interact(pet, PetActionPlay, now)
+	if err != nil {
+		return nil, err
+	}
+
+	pet.Happiness = clamp(pet.Happiness+scaleBoost(20, multiplier*happinessMultiplier(pet)), 0, 100)
+	pet.Hunger = clamp(pet.Hunger-5, 0, 100)
+	recordPetEvent(pet, PetEventPlayed, "Played with you")
+
+	if err := s.pets.save(pet, now); err != nil {
+		return nil, err
+	}
+
+	return pet, nil
+}
+
+func (s *petService) UseItem(userID uuid.UUID, itemID string) (*models.UseItemResponse, error) {
+	item, ok := catalog.FindItem(itemID)
+	if !ok {
+		return nil, errors.New("item not found")
+	}
+
+	var result *models.UseItemResponse
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		tx := s.in(repos)
+		pet, err := tx.pets.find(userID)
+		if err != nil {
+			return err
+		}
+		result, err = tx.useItem(pet, item)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// useItem takes the item out of the owner's inventory (toys are kept) and
+// applies its effects to the pet, starting the item's cooldown
+func (s *petService) useItem(pet *models.Pet, item catalog.Item) (*models.UseItemResponse, error) {
+	now := time.Now()
+
+	if item.Effects.Revive {
+		if pet.Health != PetFainted {
+			return nil, errors.New("pet has not fainted")
+		}
+	} else if pet.Health == PetFainted {
+		return nil, errors.New("pet has fainted")
+	}
+
+	availableAt, err := s.inventoryRepo.FindCooldown(pet.ID, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableAt != nil && availableAt.After(now) {
+		return nil, fmt.Errorf("%s can be used again at %s", item.Name, availableAt.UTC().Format(time.RFC3339))
+	}
+
+	if item.Consumable() {
+		consumed, err := s.inventoryRepo.Consume(pet.UserID, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, fmt.Errorf("you have no %s", item.Name)
+		}
+	} else {
+		owned, err := s.inventoryRepo.Quantity(pet.UserID, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if owned == 0 {
+			return nil, fmt.Errorf("you have no %s", item.Name)
+		}
+	}
+
+	multiplier := 1.0
+	if action := itemAction(item); action != "" {
+		if multiplier, err = s.pets.interact(pet, action, now); err != nil {
+			return nil, err
+		}
+	}
+
+	switch item.Kind {
+	case catalog.ItemFood:
+		recordPetEvent(pet, PetEventFed, "Ate a "+strings.ToLower(item.Name))
+	case catalog.ItemToy:
+		recordPetEvent(pet, PetEventPlayed, "Played with the "+strings.ToLower(item.Name))
+	default:
+		recordPetEvent(pet, PetEventItem, "Was given a "+strings.ToLower(item.Name))
+	}
+
+	var transitions []models.PetHealthTransition
+	if item.Effects.Revive {
+		transitions = append(transitions, s.pets.revive(pet, now, "revived with "+item.Name))
+	}
+
+	pet.Hunger = clamp(pet.Hunger+item.Effects.Hunger, 0, 100)
+	pet.Happiness = clamp(pet.Happiness+scaleBoost(item.Effects.Happiness, multiplier*happinessMultiplier(pet)), 0, 100)
+
+	if item.Effects.Cure && pet.Health == PetSick {
+		pet.StarvingSince = nil
+		transitions = append(transitions, setPetHealth(pet, PetHealthy, now, "cured with "+item.Name))
+	}
+
+	s.pets.cfg.Progression.AddExp(pet, item.Effects.Exp)
+
+	if err := s.pets.save(pet, now, transitions...); err != nil {
+		return nil, err
+	}
+
+	if item.Effects.Exp > 0 {
+		if err := s.achievements.publish(pet.UserID, EventPetProgressed); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.UseItemResponse{Pet: pet}
+	if item.Cooldown > 0 {
+		next := now.Add(time.Duration(item.Cooldown))
+		if err := s.inventoryRepo.SetCooldown(pet.ID, item.ID, next); err != nil {
+			return nil, err
+		}
+		result.NextAvailableAt = &next
+	}
+
+	if result.Remaining, err = s.inventoryRepo.Quantity(pet.UserID, item.ID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// itemAction is the interaction using the item counts as, if any
+func itemAction(item catalog.Item) string {
+	switch item.Kind {
+	case catalog.ItemFood:
+		return PetActionFeed
+	case catalog.ItemToy:
+		return PetActionPlay
+	}
+	return ""
+}
+
+// scaleBoost applies a multiplier (diminishing returns, accessory bonuses)
+// to a positive stat boost; penalties are never scaled
+func scaleBoost(boost int, multiplier float64) int {
+	if boost <= 0 {
+		return boost
+	}
+	return int(math.Round(float64(boost) * multiplier))
+}
+
+// petBonuses sums the bonuses of the accessories in the pet's loadout
+func petBonuses(pet *models.Pet) catalog.Bonuses {
+	var total catalog.Bonuses
+	for _, id := range pet.Loadout {
+		if accessory, ok := catalog.FindAccessory(id); ok {
+			total.ExpPercent += accessory.Bonuses.ExpPercent
+			total.HappinessPercent += accessory.Bonuses.HappinessPercent
+		}
+	}
+	return total
+}
+
+// happinessMultiplier is the pet's accessory bonus on happiness boosts
+func happinessMultiplier(pet *models.Pet) float64 {
+	return 1 + float64(petBonuses(pet).HappinessPercent)/100
+}
+
+// bestToy returns the owned toy with the biggest happiness boost that the
+// pet can use right now, or nil
+func (s *petService) bestToy(pet *models.Pet) (*catalog.Item, error) {
+	owned, err := s.inventoryRepo.FindByUserID(pet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var best *catalog.Item
+	for _, stack := range owned {
+		item, ok := catalog.FindItem(stack.ItemID)
+		if !ok || item.Kind != catalog.ItemToy {
+			continue
+		}
+		if best != nil && item.Effects.Happiness <= best.Effects.Happiness {
+			continue
+		}
+		availableAt, err := s.inventoryRepo.FindCooldown(pet.ID, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if availableAt != nil && availableAt.After(now) {
+			continue
+		}
+		best = &item
+	}
+
+	return best, nil
+}
+
+func (s *petService) GetHealth(userID uuid.UUID) (*models.PetHealthResponse, error) {
+	pet, err := s.GetPet(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.petRepo.FindHealthTransitions(pet.ID, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PetHealthResponse{
+		Health:          pet.Health,
+		HealthChangedAt: pet.HealthChangedAt,
+		StarvingSince:   pet.StarvingSince,
+		RevivalProgress: pet.RevivalProgress,
+		ReviveGoldCost:  s.pets.cfg.ReviveGoldCost,
+		ReviveQuests:    s.pets.cfg.ReviveQuests,
+		Transitions:     transitions,
+	}, nil
+}
+
+func (s *petService) RevivePet(userID uuid.UUID, method string, idempotencyKey string) (*models.Pet, error) {
+	var result *models.Pet
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		result, err = s.in(repos).revivePet(userID, method, idempotencyKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *petService) revivePet(userID uuid.UUID, method string, idempotencyKey string) (*models.Pet, error) {
+	pet, err := s.pets.find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pet.Health != PetFainted {
+		return nil, errors.New("pet has not fainted")
+	}
+
+	reason := "revived with gold"
+	switch method {
+	case "gold":
+		entry := &models.GoldTransaction{
+			UserID:  userID,
+			Amount:  -s.pets.cfg.ReviveGoldCost,
+			Reason:  models.GoldPetRevival,
+			RefType: "pet",
+			RefID:   pet.ID.String(),
+		}
+		if idempotencyKey != "" {
+			key := "revive:" + pet.ID.String() + ":" + idempotencyKey
+			entry.IdempotencyKey = &key
+		}
+		applied, err := s.ledgerRepo.Apply(entry)
+		if err != nil {
+			return nil, err
+		}
+		// The revival this key paid for already happened; the pet has
+		// fainted again since
+		if !applied {
+			return pet, nil
+		}
+	case "quests":
+		if remaining := s.pets.cfg.ReviveQuests - pet.RevivalProgress; remaining > 0 {
+			return nil, fmt.Errorf("complete %d more quests to revive your pet", remaining)
+		}
+		reason = "revived by completing quests"
+	default:
+		return nil, errors.New("invalid revival method")
+	}
+
+	now := time.Now()
+	transition := s.pets.revive(pet, now, reason)
+
+	if err := s.pets.save(pet, now, transition); err != nil {
+		return nil, err
+	}
+
+	return pet, nil
+}
+
+// DecayAll brings every pet's stats up to date; run periodically so pets
+// decay even when their owner never opens the app.
+func (s *petService) DecayAll() (int, error) {
+	now := time.Now()
+	decayed := 0
+
+	err := s.petRepo.FindInBatches(100, func(pets []models.Pet) error {
+		for i := range pets {
+			changed, err := s.pets.advance(&pets[i], now)
+			if err != nil {
+				return err
+			}
+			if changed {
+				decayed++
+			}
+		}
+		return nil
+	})
+
+	return decayed, err
+}
+
+// internal/services/pet_clock.go
+
+// Pet health states
+const (
+	PetHealthy = "healthy"
+	PetSick    = "sick"
+	PetFainted = "fainted"
+)
+
+// Pet moods
+const (
+	PetMoodEcstatic = "ecstatic"
+	PetMoodContent  = "content"
+	PetMoodBored    = "bored"
+	PetMoodHungry   = "hungry"
+	PetMoodSulking  = "sulking"
+)
+
+// Pet event kinds
+const (
+	PetEventHatched = "hatched"
+	PetEventQuest   = "quest"
+	PetEventFed     = "fed"
+	PetEventPlayed  = "played"
+	PetEventItem    = "item"
+	PetEventLevel   = "level"
+	PetEventEvolved = "evolved"
+	PetEventHealth  = "health"
+	PetEventMood    = "mood"
+)
+
+// moodMessages is logged when the pet's mood changes to the key
+var moodMessages = map[string]string{
+	PetMoodEcstatic: "Is bouncing off the walls with joy",
+	PetMoodBored:    "Got bored while you were away",
+	PetMoodHungry:   "Is getting hungry",
+	PetMoodSulking:  "Is sulking in a corner",
+}
+
+// Pet interactions subject to cooldowns
+const (
+	PetActionFeed = "feed"
+	PetActionPlay = "play"
+)
+
+// PetConfig holds the tunable pet simulation rules
+type PetConfig struct {
+	// Every DecayInterval the pet loses HungerDecay and HappinessDecay
+	// points, never dropping below StatFloor
+	DecayInterval  time.Duration
+	HungerDecay    int
+	HappinessDecay int
+	StatFloor      int
+
+	// A pet at zero hunger for SickAfter falls sick and loses SickExpLoss
+	// EXP per decay interval; sick and starving for FaintAfter, it faints.
+	// Feeding it up to CureHunger cures it.
+	SickAfter   time.Duration
+	FaintAfter  time.Duration
+	SickExpLoss int
+	CureHunger  int
+
+	// A fainted pet is revived for ReviveGoldCost gold or after its owner
+	// completes ReviveQuests quests, coming back with at least CureHunger
+	// hunger and ReviveHappiness happiness
+	ReviveGoldCost  int
+	ReviveQuests    int
+	ReviveHappiness int
+
+	Progression PetProgression
+
+	// Each interaction (PetActionFeed, PetActionPlay) can be used once per
+	// cooldown; repeats within InteractionWindow multiply its happiness
+	// boost by DiminishingFactor per earlier use
+	Cooldowns         map[string]time.Duration
+	InteractionWindow time.Duration
+	DiminishingFactor float64
+}
+
+func NewPetConfig(cfg *config.Config) PetConfig {
+	// At zero either way, a starving pet would fall sick and count as
+	// cured in the same instant, forever
+	if cfg.PetCureHunger <= 0 {
+		log.Fatal("PET_CURE_HUNGER must be positive")
+	}
+	if cfg.PetSickAfter <= 0 {
+		log.Fatal("PET_SICK_AFTER must be positive")
+	}
+
+	return PetConfig{
+		DecayInterval:   cfg.PetDecayInterval,
+		HungerDecay:     cfg.PetHungerDecay,
+		HappinessDecay:  cfg.PetHappinessDecay,
+		StatFloor:       cfg.PetStatFloor,
+		SickAfter:       cfg.PetSickAfter,
+		FaintAfter:      cfg.PetFaintAfter,
+		SickExpLoss:     cfg.PetSickExpLoss,
+		CureHunger:      cfg.PetCureHunger,
+		ReviveGoldCost:  cfg.PetReviveGoldCost,
+		ReviveQuests:    cfg.PetReviveQuests,
+		ReviveHappiness: cfg.PetReviveHappiness,
+		Progression: PetProgression{
+			BaseExp:      cfg.PetBaseExp,
+			ExpGrowth:    cfg.PetExpGrowth,
+			MaxLevel:     cfg.PetMaxLevel,
+			ExpPerReward: cfg.PetExpPerReward,
+			MinQuestExp:  cfg.PetMinQuestExp,
+			MaxQuestExp:  cfg.PetMaxQuestExp,
+		},
+		Cooldowns: map[string]time.Duration{
+			PetActionFeed: cfg.PetFeedCooldown,
+			PetActionPlay: cfg.PetPlayCooldown,
+		},
+		InteractionWindow: cfg.PetInteractionWindow,
+		DiminishingFactor: cfg.PetDiminishingFactor,
+	}
+}
+
+// petClock loads pets with elapsed time applied: stat decay and the
+// resulting health changes. Decay is computed lazily from DecayedAt in
+// whole intervals, so partial intervals carry over to the next read.
+type petClock struct {
+	petRepo      repositories.PetRepository
+	vacationRepo repositories.VacationRepository
+	cfg          PetConfig
+}
+
+// in returns the clock working through repos
+func (c petClock) in(repos repositories.Repositories) petClock {
+	c.petRepo = repos.Pets
+	c.vacationRepo = repos.Vacations
+	return c
+}
+
+// find loads the user's active pet
+func (c petClock) find(userID uuid.UUID) (*models.Pet, error) {
+	pet, err := c.petRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := c.advance(pet, now); err != nil {
+		return nil, err
+	}
+	return pet, c.annotate(pet, now)
+}
+
+func (c petClock) findByID(petID uuid.UUID) (*models.Pet, error) {
+	pet, err := c.petRepo.FindByID(petID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := c.advance(pet, now); err != nil {
+		return nil, err
+	}
+	return pet, c.annotate(pet, now)
+}
+
+func (c petClock) findAll(userID uuid.UUID) ([]models.Pet, error) {
+	pets, err := c.petRepo.FindAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range pets {
+		if _, err := c.advance(&pets[i], now); err != nil {
+			return nil, err
+		}
+		if err := c.annotate(&pets[i], now); err != nil {
+			return nil, err
+		}
+	}
+	return pets, nil
+}
+
+// advance applies and persists any decay due at now. It reports whether
+// the pet changed.
+func (c petClock) advance(pet *models.Pet, now time.Time) (bool, error) {
+	if c.cfg.DecayInterval <= 0 {
+		return false, nil
+	}
+
+	prev := pet.DecayedAt
+	start := pet.UpdatedAt
+	if prev != nil {
+		start = *prev
+	}
+
+	if now.Sub(start) < c.cfg.DecayInterval {
+		return false, nil
+	}
+
+	// Time the owner spends on vacation doesn't count
+	away, err := c.awayTime(pet, start, now)
+	if err != nil {
+		return false, err
+	}
+
+	steps := int(away.activeBetween(start, now) / c.cfg.DecayInterval)
+	if steps <= 0 {
+		return false, nil
+	}
+
+	end := away.activeAdd(start, time.Duration(steps)*c.cfg.DecayInterval)
+	pet.DecayedAt = &end
+
+	var transitions []models.PetHealthTransition
+	if pet.Health != PetFainted {
+		species := catalog.SpeciesOrDefault(pet.Type)
+		hungerRate := float64(c.cfg.HungerDecay) * species.HungerDecay
+		happinessRate := float64(c.cfg.HappinessDecay) * species.HappinessDecay
+
+		hunger, hungerCarry := pet.Hunger, pet.HungerCarry
+		var hungerLoss, happinessLoss int
+		hungerLoss, pet.HungerCarry = decayPoints(steps, hungerRate, pet.HungerCarry)
+		happinessLoss, pet.HappinessCarry = decayPoints(steps, happinessRate, pet.HappinessCarry)
+		pet.Hunger = decayStat(pet.Hunger, hungerLoss, c.cfg.StatFloor)
+		pet.Happiness = decayStat(pet.Happiness, happinessLoss, c.cfg.StatFloor)
+
+		// Date starvation from the interval where hunger actually ran out
+		if pet.Hunger <= 0 && pet.StarvingSince == nil && hungerRate > 0 {
+			toZero := max(int(math.Ceil((float64(max(hunger, 0))-hungerCarry)/hungerRate)), 0)
+			at := away.activeAdd(start, time.Duration(toZero)*c.cfg.DecayInterval)
+			pet.StarvingSince = &at
+		}
+
+		wasSick := pet.Health == PetSick
+		sickFrom := start
+		transitions = c.updateHealth(pet, end, away)
+
+		// EXP drains for the part of the window the pet spent sick
+		sickTo := end
+		for _, t := range transitions {
+			if t.To == PetSick {
+				wasSick, sickFrom = true, t.CreatedAt
+			}
+			if t.From == PetSick {
+				sickTo = t.CreatedAt
+			}
+		}
+		if wasSick && sickTo.After(sickFrom) {
+			c.cfg.Progression.RemoveExp(pet, int(away.activeBetween(sickFrom, sickTo)/c.cfg.DecayInterval)*c.cfg.SickExpLoss)
+		}
+	}
+
+	saved, err := c.petRepo.SaveDecay(pet, prev)
+	if err != nil {
+		return false, err
+	}
+	if !saved {
+		// Someone else advanced this pet first; use their result
+		fresh, err := c.petRepo.FindByID(pet.ID)
+		if err != nil {
+			return false, err
+		}
+		*pet = *fresh
+		return true, nil
+	}
+
+	if err := c.petRepo.AddHealthTransitions(transitions); err != nil {
+		return false, err
+	}
+
+	if err := c.flushEvents(pet, transitions); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// save re-evaluates the pet's health after a stat change and persists it
+// along with any health transitions.
+func (c petClock) save(pet *models.Pet, now time.Time, transitions ...models.PetHealthTransition) error {
+	away, err := c.awayTime(pet, now, now)
+	if err != nil {
+		return err
+	}
+	transitions = append(transitions, c.updateHealth(pet, now, away)...)
+
+	evolutions, err := c.evolve(pet, now)
+	if err != nil {
+		return err
+	}
+
+	if err := c.petRepo.Update(pet); err != nil {
+		return err
+	}
+
+	for i := range evolutions {
+		if err := c.petRepo.AddEvolution(&evolutions[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := c.petRepo.AddHealthTransitions(transitions); err != nil {
+		return err
+	}
+
+	if err := c.flushEvents(pet, transitions); err != nil {
+		return err
+	}
+
+	return c.annotate(pet, now)
+}
+
+// flushEvents writes the pet's pending events, plus one per health
+// transition, to its event log
+func (c petClock) flushEvents(pet *models.Pet, transitions []models.PetHealthTransition) error {
+	events := pet.PendingEvents
+	for _, t := range transitions {
+		events = append(events, models.PetEvent{
+			Kind:      PetEventHealth,
+			Message:   capitalize(t.Reason),
+			CreatedAt: t.CreatedAt,
+		})
+	}
+	pet.PendingEvents = nil
+
+	for i := range events {
+		events[i].PetID = pet.ID
+		events[i].UserID = pet.UserID
+	}
+	return c.petRepo.AddEvents(events)
+}
+
+// interact uses one of the pet's action, failing while the action is on
+// cooldown. It returns the multiplier for the action's happiness boost,
+// which shrinks the more often the action was used in the current window.
+func (c petClock) interact(pet *models.Pet, action string, now time.Time) (float64, error) {
+	cooldown := c.cfg.Cooldowns[action]
+	multiplier := 1.0
+
+	err := c.petRepo.UpdateInteraction(pet.ID, action, func(interaction *models.PetInteraction) error {
+		if interaction.LastAt != nil {
+			if next := interaction.LastAt.Add(cooldown); next.After(now) {
+				return fmt.Errorf("you can %s your pet again at %s", action, next.UTC().Format(time.RFC3339))
+			}
+		}
+
+		if now.Sub(interaction.WindowStart) >= c.cfg.InteractionWindow {
+			interaction.WindowStart = now
+			interaction.Count = 0
+		}
+
+		multiplier = math.Pow(c.cfg.DiminishingFactor, float64(interaction.Count))
+		interaction.Count++
+		interaction.LastAt = &now
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return multiplier, nil
+}
+
+// annotate fills in the pet's derived state: its loadout, when its
+// interactions come off cooldown and its mood
+func (c petClock) annotate(pet *models.Pet, now time.Time) error {
+	equipment, err := c.petRepo.FindEquipment(pet.ID)
+	if err != nil {
+		return err
+	}
+
+	pet.Loadout = make(map[string]string, len(equipment))
+	for _, e := range equipment {
+		pet.Loadout[e.Slot] = e.AccessoryID
+	}
+
+	interactions, err := c.petRepo.FindInteractions(pet.ID)
+	if err != nil {
+		return err
+	}
+
+	pet.NextAvailableAt = map[string]time.Time{}
+	for _, interaction := range interactions {
+		if interaction.LastAt == nil {
+			continue
+		}
+		if next := interaction.LastAt.Add(c.cfg.Cooldowns[interaction.Action]); next.After(now) {
+			pet.NextAvailableAt[interaction.Action] = next
+		}
+	}
+
+	return c.updateMood(pet, now)
+}
+
+// updateMood stores the pet's current mood, logging the change. A pet
+// whose owner is on vacation is content.
+func (c petClock) updateMood(pet *models.Pet, now time.Time) error {
+	away, err := c.awayTime(pet, now, now)
+	if err != nil {
+		return err
+	}
+
+	mood := PetMoodContent
+	if !away.contains(now) {
+		mood = petMood(pet, now)
+	}
+	if mood == pet.Mood {
+		return nil
+	}
+
+	changed, err := c.petRepo.UpdateMood(pet.ID, pet.Mood, mood)
+	if err != nil {
+		return err
+	}
+	pet.Mood = mood
+	if !changed {
+		// A concurrent request logged this change
+		return nil
+	}
+
+	if message, ok := moodMessages[mood]; ok {
+		return c.petRepo.AddEvents([]models.PetEvent{{
+			PetID:     pet.ID,
+			UserID:    pet.UserID,
+			Kind:      PetEventMood,
+			Message:   message,
+			CreatedAt: now,
+		}})
+	}
+	return nil
+}
+
+// petMood derives a mood from the pet's stats, how recently its owner
+// completed a quest and the time of day (server time). At night the pet
+// sleeps, so an idle owner doesn't bore it.
+func petMood(pet *models.Pet, now time.Time) string {
+	hour := now.Hour()
+	night := hour >= 22 || hour < 6
+	sinceQuest := time.Duration(math.MaxInt64)
+	if pet.LastQuestAt != nil {
+		sinceQuest = now.Sub(*pet.LastQuestAt)
+	}
+
+	switch {
+	case pet.Health != PetHealthy || pet.Happiness < 25:
+		return PetMoodSulking
+	case pet.Hunger < 25:
+		return PetMoodHungry
+	case pet.Hunger >= 80 && pet.Happiness >= 80 && sinceQuest < 6*time.Hour:
+		return PetMoodEcstatic
+	case !night && (sinceQuest >= 24*time.Hour || pet.Happiness < 50):
+		return PetMoodBored
+	default:
+		return PetMoodContent
+	}
+}
+
+// recordPetEvent queues an entry for the pet's event log; it is written
+// when the pet is saved
+func recordPetEvent(pet *models.Pet, kind, message string) {
+	pet.PendingEvents = append(pet.PendingEvents, models.PetEvent{Kind: kind, Message: message, CreatedAt: time.Now()})
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// evolve moves the pet along its species' evolution tree for every
+// threshold its level has reached, picking each branch from the categories
+// of quests completed with it.
+func (c petClock) evolve(pet *models.Pet, now time.Time) ([]models.PetEvolution, error) {
+	var evolutions []models.PetEvolution
+	var tallies map[string]int
+
+	for {
+		next, ok := catalog.NextEvolution(pet.Type, pet.Form)
+		if !ok || pet.Level < next.Level {
+			return evolutions, nil
+		}
+
+		if tallies == nil {
+			var err error
+			if tallies, err = c.petRepo.FindQuestTallies(pet.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		branch, ok := next.Choose(tallies)
+		if !ok {
+			return evolutions, nil
+		}
+
+		evolutions = append(evolutions, models.PetEvolution{
+			PetID:     pet.ID,
+			UserID:    pet.UserID,
+			FromForm:  pet.Form,
+			ToForm:    branch.Form,
+			Level:     pet.Level,
+			Category:  branch.Category,
+			CreatedAt: now,
+		})
+		recordPetEvent(pet, PetEventEvolved, "Evolved into a "+branch.Name)
+		pet.Form = branch.Form
+	}
+}
+
+// awayTime loads the owner's vacations that can affect the pet's clocks
+// between from (or its oldest running health timer) and now
+func (c petClock) awayTime(pet *models.Pet, from, now time.Time) (awayTime, error) {
+	for _, t := range []*time.Time{pet.StarvingSince, pet.HealthChangedAt} {
+		if t != nil && t.Before(from) {
+			from = *t
+		}
+	}
+
+	vacations, err := c.vacationRepo.FindOverlapping(pet.UserID, from, now.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	return awayTime(vacations), nil
+}
+
+// awayTime is a set of non-overlapping vacations, oldest first. Pet
+// clocks measure "active" time, which skips them.
+type awayTime []models.Vacation
+
+// activeBetween is the active time in [from, to)
+func (a awayTime) activeBetween(from, to time.Time) time.Duration {
+	d := to.Sub(from)
+	for _, v := range a {
+		start, end := v.StartsAt, v.End()
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			d -= end.Sub(start)
+		}
+	}
+	return max(d, 0)
+}
+
+// activeAdd returns when d of active time will have passed since from
+func (a awayTime) activeAdd(from time.Time, d time.Duration) time.Time {
+	t := from
+	for _, v := range a {
+		end := v.End()
+		if !end.After(t) {
+			continue
+		}
+		if v.StartsAt.After(t) {
+			gap := v.StartsAt.Sub(t)
+			if d <= gap {
+				return t.Add(d)
+			}
+			d -= gap
+		}
+		t = end
+	}
+	return t.Add(d)
+}
+
+// contains reports whether t falls in a vacation
+func (a awayTime) contains(t time.Time) bool {
+	for _, v := range a {
+		if !t.Before(v.StartsAt) && t.Before(v.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// revive brings a fainted pet back with enough food and cheer to stay up,
+// restarting its decay clock
+func (c petClock) revive(pet *models.Pet, now time.Time, reason string) models.PetHealthTransition {
+	transition := setPetHealth(pet, PetHealthy, now, reason)
+	pet.Hunger = max(pet.Hunger, c.cfg.CureHunger)
+	pet.Happiness = max(pet.Happiness, c.cfg.ReviveHappiness)
+	pet.StarvingSince = nil
+	pet.RevivalProgress = 0
+	pet.DecayedAt = &now
+	return transition
+}
+
+// updateHealth runs the health state machine up to at, returning the
+// transitions taken (stamped with when they happened). Sickness and
+// fainting timers stand still while the owner is away.
+func (c petClock) updateHealth(pet *models.Pet, at time.Time, away awayTime) []models.PetHealthTransition {
+	var transitions []models.PetHealthTransition
+
+	for {
+		switch pet.Health {
+		case PetSick:
+			if pet.Hunger >= c.cfg.CureHunger {
+				pet.StarvingSince = nil
+				transitions = append(transitions, setPetHealth(pet, PetHealthy, at, "recovered after eating"))
+				continue
+			}
+			if pet.Hunger > 0 || pet.HealthChangedAt == nil {
+				return transitions
+			}
+			faintAt := away.activeAdd(*pet.HealthChangedAt, c.cfg.FaintAfter)
+			if faintAt.After(at) {
+				return transitions
+			}
+			transitions = append(transitions, setPetHealth(pet, PetFainted, faintAt, "fainted from hunger"))
+
+		case PetFainted:
+			return transitions
+
+		default:
+			if pet.Hunger > 0 {
+				pet.StarvingSince = nil
+				return transitions
+			}
+			if pet.StarvingSince == nil {
+				pet.StarvingSince = &at
+			}
+			sickAt := away.activeAdd(*pet.StarvingSince, c.cfg.SickAfter)
+			if sickAt.After(at) {
+				return transitions
+			}
+			transitions = append(transitions, setPetHealth(pet, PetSick, sickAt, "fell sick from hunger"))
+		}
+	}
+}
+
+func setPetHealth(pet *models.Pet, health string, at time.Time, reason string) models.PetHealthTransition {
+	from := pet.Health
+	if from == "" {
+		from = PetHealthy
+	}
+
+	pet.Health = health
+	pet.HealthChangedAt = &at
+	if health == PetFainted {
+		pet.RevivalProgress = 0
+	}
+
+	return models.PetHealthTransition{
+		PetID:     pet.ID,
+		UserID:    pet.UserID,
+		From:      from,
+		To:        health,
+		Reason:    reason,
+		CreatedAt: at,
+	}
+}
+
+// decayPoints splits steps intervals of decay at rate, plus the fraction
+// carried from earlier, into the whole points lost now and the fraction
+// carried on
+func decayPoints(steps int, rate, carry float64) (int, float64) {
+	total := float64(steps)*rate + carry
+	// The epsilon keeps float error from holding back a point that is due
+	points := math.Floor(total + 1e-9)
+	return int(points), max(total-points, 0)
+}
+
+func decayStat(value, loss, floor int) int {
+	if value <= floor {
+		return value
+	}
+	return max(value-loss, floor)
+}
+
+// internal/services/pet_progression.go
+
+// PetProgression is the pet levelling curve: what quests are worth and how
+// much EXP each level takes. Every EXP change goes through it.
+type PetProgression struct {
+	// Leaving level L takes BaseExp * L^ExpGrowth EXP, scaled by the
+	// species' EXP curve
+	BaseExp   int
+	ExpGrowth float64
+	// Pets stop at MaxLevel; EXP past it is discarded
+	MaxLevel int
+	// Quests award ExpPerReward EXP per gold of reward, clamped to
+	// [MinQuestExp, MaxQuestExp]
+	ExpPerReward float64
+	MinQuestExp  int
+	MaxQuestExp  int
+}
+
+// ExpToNextLevel is the EXP a pet of the species needs to leave level
+func (p PetProgression) ExpToNextLevel(species string, level int) int {
+	required := float64(p.BaseExp) * math.Pow(float64(level), p.ExpGrowth) * catalog.SpeciesOrDefault(species).ExpCurve
+	return max(int(math.Round(required)), 1)
+}
+
+// QuestExp is the EXP earned for completing a quest with the given reward
+func (p PetProgression) QuestExp(reward int) int {
+	return clamp(int(math.Round(float64(reward)*p.ExpPerReward)), p.MinQuestExp, p.MaxQuestExp)
+}
+
+// AddExp grants EXP, carrying the overflow across as many level ups as it
+// pays for. It returns the number of levels gained.
+func (p PetProgression) AddExp(pet *models.Pet, exp int) int {
+	if exp <= 0 {
+		return 0
+	}
+	exp += exp * petBonuses(pet).ExpPercent / 100
+
+	gained := 0
+	pet.Exp += exp
+	for !p.atMaxLevel(pet) {
+		required := p.ExpToNextLevel(pet.Type, pet.Level)
+		if pet.Exp < required {
+			break
+		}
+		pet.Exp -= required
+		pet.Level++
+		gained++
+		recordPetEvent(pet, PetEventLevel, fmt.Sprintf("Levelled up to %d", pet.Level))
+	}
+
+	if p.atMaxLevel(pet) {
+		pet.Level = p.MaxLevel
+		pet.Exp = 0
+	}
+	return gained
+}
+
+// RemoveExp takes EXP away, dropping levels (but never below 1) as needed.
+func (p PetProgression) RemoveExp(pet *models.Pet, exp int) {
+	pet.Exp -= exp
+
+	for pet.Exp < 0 && pet.Level > 1 {
+		pet.Level--
+		pet.Exp += p.ExpToNextLevel(pet.Type, pet.Level)
+		recordPetEvent(pet, PetEventLevel, fmt.Sprintf("Dropped back to level %d", pet.Level))
+	}
+	if pet.Exp < 0 {
+		pet.Exp = 0
+	}
+}
+
+func (p PetProgression) atMaxLevel(pet *models.Pet) bool {
+	return p.MaxLevel > 0 && pet.Level >= p.MaxLevel
+}
+
+// internal/services/item_service.go
+
+type ItemService interface {
+	GetShopItems() []catalog.Item
+	GetInventory(userID uuid.UUID) ([]models.InventoryItem, error)
+	BuyItem(userID uuid.UUID, itemID string, quantity int, idempotencyKey string) (*models.InventoryItem, error)
+}
+
+type itemService struct {
+	inventoryRepo repositories.InventoryRepository
+	ledgerRepo    repositories.LedgerRepository
+	uow           repositories.UnitOfWork
+}
+
+func NewItemService(inventoryRepo repositories.InventoryRepository, ledgerRepo repositories.LedgerRepository, uow repositories.UnitOfWork) ItemService {
+	return &itemService{inventoryRepo: inventoryRepo, ledgerRepo: ledgerRepo, uow: uow}
+}
+
+func (s *itemService) GetShopItems() []catalog.Item {
+	return catalog.AllItems()
+}
+
+func (s *itemService) GetInventory(userID uuid.UUID) ([]models.InventoryItem, error) {
+	return s.inventoryRepo.FindByUserID(userID)
+}
+
+// BuyItem buys quantity of the item. Retrying with the same idempotency
+// key doesn't buy it again.
+func (s *itemService) BuyItem(userID uuid.UUID, itemID string, quantity int, idempotencyKey string) (*models.InventoryItem, error) {
+	item, ok := catalog.FindItem(itemID)
+	if !ok {
+		return nil, errors.New("item not found")
+	}
+
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	var owned int
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		if err := buyItem(repos.Ledger, repos.Inventory, userID, item, quantity, idempotencyKey); err != nil {
+			return err
+		}
+		var err error
+		owned, err = repos.Inventory.Quantity(userID, item.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InventoryItem{UserID: userID, ItemID: item.ID, Quantity: owned}, nil
+}
+
+// buyItem charges the user for quantity of the item and adds it to their
+// inventory, unless the idempotency key (if any) was already used
+func buyItem(ledgerRepo repositories.LedgerRepository, inventoryRepo repositories.InventoryRepository, userID uuid.UUID, item catalog.Item, quantity int, idempotencyKey string) error {
+	if item.Kind == catalog.ItemToy {
+		owned, err := inventoryRepo.Quantity(userID, item.ID)
+		if err != nil {
+			return err
+		}
+		if owned+quantity > 1 {
+			return errors.New("you can only own one of each toy")
+		}
+	}
+
+	entry := &models.GoldTransaction{
+		UserID:  userID,
+		Amount:  -item.Price * quantity,
+		Reason:  models.GoldItemPurchase,
+		RefType: "item",
+		RefID:   item.ID,
+	}
+	if idempotencyKey != "" {
+		// Scoped to the item, so reusing a key for another item buys it
+		key := "item:" + item.ID + ":" + idempotencyKey
+		entry.IdempotencyKey = &key
+	}
+
+	applied, err := ledgerRepo.Apply(entry)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return nil
+	}
+
+	return inventoryRepo.Add(userID, item.ID, quantity)
+}
+
+// internal/services/vacation_service.go
+
+// recentVacations is how many past vacations GetVacation lists
+const recentVacations = 10
+
+type VacationService interface {
+	GetVacation(userID uuid.UUID) (*models.VacationResponse, error)
+	StartVacation(userID uuid.UUID, req models.StartVacationRequest) (*models.Vacation, error)
+	EndVacation(userID uuid.UUID) error
+}
+
+type vacationService struct {
+	vacationRepo repositories.VacationRepository
+	uow          repositories.UnitOfWork
+	maxDays      int
+	perYear      int
+}
+
+func NewVacationService(vacationRepo repositories.VacationRepository, uow repositories.UnitOfWork, maxDays, perYear int) VacationService {
+	return &vacationService{vacationRepo: vacationRepo, uow: uow, maxDays: maxDays, perYear: perYear}
+}
+
+func (s *vacationService) GetVacation(userID uuid.UUID) (*models.VacationResponse, error) {
+	now := time.Now()
+
+	current, err := s.vacationRepo.FindScheduled(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.vacationRepo.FindRecent(userID, recentVacations)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := s.remaining(s.vacationRepo, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VacationResponse{
+		Current:   current,
+		Recent:    recent,
+		Remaining: remaining,
+		MaxDays:   s.maxDays,
+	}, nil
+}
+
+func (s *vacationService) StartVacation(userID uuid.UUID, req models.StartVacationRequest) (*models.Vacation, error) {
+	now := time.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	startDay := today
+	if req.StartDate != "" {
+		day, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date")
+		}
+		startDay = day
+	}
+	endDay, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end date")
+	}
+
+	if startDay.Before(today) {
+		return nil, errors.New("vacation cannot start in the past")
+	}
+	if endDay.Before(startDay) {
+		return nil, errors.New("vacation must end on or after its start date")
+	}
+	if days := int(endDay.Sub(startDay)/(24*time.Hour)) + 1; days > s.maxDays {
+		return nil, fmt.Errorf("vacation can last at most %d days", s.maxDays)
+	}
+
+	// A vacation starting today starts now, so decay already applied is kept
+	startsAt := startDay
+	if startDay.Equal(today) {
+		startsAt = now
+	}
+
+	vacation := &models.Vacation{
+		UserID:   userID,
+		StartsAt: startsAt,
+		EndsAt:   endDay.AddDate(0, 0, 1),
+	}
+
+	// The user lock serialises requests so the one-scheduled rule and the
+	// yearly limit hold, and vacations never overlap
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+
+		scheduled, err := repos.Vacations.FindScheduled(userID, now)
+		if err != nil {
+			return err
+		}
+		if scheduled != nil {
+			return errors.New("you already have a vacation scheduled")
+		}
+
+		remaining, err := s.remaining(repos.Vacations, userID, now)
+		if err != nil {
+			return err
+		}
+		if remaining <= 0 {
+			return fmt.Errorf("you can take at most %d vacations a year", s.perYear)
+		}
+
+		return repos.Vacations.Create(vacation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vacation, nil
+}
+
+// EndVacation cuts the current vacation short, or cancels an upcoming one
+// (which then doesn't count toward the yearly limit)
+func (s *vacationService) EndVacation(userID uuid.UUID) error {
+	now := time.Now()
+
+	return s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+
+		vacation, err := repos.Vacations.FindScheduled(userID, now)
+		if err != nil {
+			return err
+		}
+		if vacation == nil {
+			return errors.New("no vacation scheduled")
+		}
+
+		if vacation.StartsAt.After(now) {
+			return repos.Vacations.Delete(vacation.ID)
+		}
+
+		vacation.EndedAt = &now
+		return repos.Vacations.Update(vacation)
+	})
+}
+
+func (s *vacationService) remaining(vacationRepo repositories.VacationRepository, userID uuid.UUID, now time.Time) (int, error) {
+	used, err := vacationRepo.CountStartedSince(userID, now.AddDate(-1, 0, 0))
+	if err != nil {
+		return 0, err
+	}
+	return max(s.perYear-int(used), 0), nil
+}
+
+// internal/services/decoration_service.go
+
+type DecorationService interface {
+	GetDecorations(userID uuid.UUID) ([]models.Decoration, error)
+	BuyDecoration(userID uuid.UUID, decoration string) error
+	GetShopDecorations() []catalog.Decoration
+	GetAccessories() []catalog.Accessory
+}
+
+type decorationService struct {
+	decorationRepo repositories.DecorationRepository
+	ledgerRepo     repositories.LedgerRepository
+	petRepo        repositories.PetRepository
+	uow            repositories.UnitOfWork
+}
+
+func NewDecorationService(decorationRepo repositories.DecorationRepository, ledgerRepo repositories.LedgerRepository, petRepo repositories.PetRepository, uow repositories.UnitOfWork) DecorationService {
+	return &decorationService{decorationRepo: decorationRepo, ledgerRepo: ledgerRepo, petRepo: petRepo, uow: uow}
+}
+
+func (s *decorationService) GetDecorations(userID uuid.UUID) ([]models.Decoration, error) {
+	return s.decorationRepo.FindByUserID(userID)
+}
+
+// GetShopDecorations lists the catalog decorations on sale right now
+func (s *decorationService) GetShopDecorations() []catalog.Decoration {
+	now := time.Now()
+	var onSale []catalog.Decoration
+	for _, d := range catalog.AllDecorations() {
+		if d.OnSale(now) {
+			onSale = append(onSale, d)
+		}
+	}
+	return onSale
+}
+
+func (s *decorationService) GetAccessories() []catalog.Accessory {
+	return catalog.AllAccessories()
+}
+
+func (s *decorationService) BuyDecoration(userID uuid.UUID, decoration string) error {
+	// Check if already owned
+	exists, err := s.decorationRepo.Exists(userID, decoration)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("decoration already owned")
+	}
+
+	price, err := s.decorationPrice(userID, decoration)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(func(repos repositories.Repositories) error {
+		// Paying locks the user's balance, so of two concurrent purchases
+		// only the first finds the decoration unowned below; the other
+		// rolls back its charge
+		if _, err := repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:  userID,
+			Amount:  -price,
+			Reason:  models.GoldDecoration,
+			RefType: "decoration",
+			RefID:   decoration,
+		}); err != nil {
+			return err
+		}
+
+		owned, err := repos.Decorations.Exists(userID, decoration)
+		if err != nil {
+			return err
+		}
+		if owned {
+			return errors.New("decoration already owned")
+		}
+
+		// Create decoration
+		dec := &models.Decoration{
+			UserID:     userID,
+			Decoration: decoration,
+		}
+
+		if err := repos.Decorations.Create(dec); err != nil {
+			return err
+		}
+
+		return achievementEngine{repos: repos}.publish(userID, EventDecorationBought)
+	})
+}
+
+// decorationPrice checks the user may buy the decoration (a catalog
+// decoration or an accessory) and returns its price
+func (s *decorationService) decorationPrice(userID uuid.UUID, id string) (int, error) {
+	if accessory, ok := catalog.FindAccessory(id); ok {
+		return accessory.Price, nil
+	}
+
+	decoration, ok := catalog.FindDecoration(id)
+	if !ok {
+		return 0, errors.New("decoration not found")
+	}
+	if !decoration.OnSale(time.Now()) {
+		return 0, errors.New("decoration is not on sale")
+	}
+
+	if decoration.MinLevel > 0 {
+		level, err := s.petRepo.MaxLevelByUserID(userID)
+		if err != nil {
+			return 0, err
+		}
+		if level < decoration.MinLevel {
+			return 0, fmt.Errorf("raise a pet to level %d to buy this decoration", decoration.MinLevel)
+		}
+	}
+
+	return decoration.Price, nil
+}
+
+// internal/services/lair_service.go
+
+// The lair is a lairWidth x lairHeight grid of cells
+const (
+	lairWidth         = 12
+	lairHeight        = 8
+	maxLayoutsPerUser = 10
+)
+
+type LairService interface {
+	GetLayouts(userID uuid.UUID) (*models.LairResponse, error)
+	GetLayout(userID uuid.UUID, layoutID uuid.UUID) (*models.LairLayout, error)
+	CreateLayout(userID uuid.UUID, req models.LairLayoutRequest) (*models.LairLayout, error)
+	SaveLayout(userID uuid.UUID, layoutID uuid.UUID, req models.LairLayoutRequest) (*models.LairLayout, error)
+	DeleteLayout(userID uuid.UUID, layoutID uuid.UUID) error
+	ActivateLayout(userID uuid.UUID, layoutID uuid.UUID) (*models.LairLayout, error)
+	PlaceDecoration(userID uuid.UUID, layoutID uuid.UUID, req models.PlaceDecorationRequest) (*models.LairLayout, error)
+	RemoveDecoration(userID uuid.UUID, layoutID uuid.UUID, decoration string) (*models.LairLayout, error)
+}
+
+type lairService struct {
+	lairRepo       repositories.LairRepository
+	decorationRepo repositories.DecorationRepository
+	uow            repositories.UnitOfWork
+}
+
+func NewLairService(lairRepo repositories.LairRepository, decorationRepo repositories.DecorationRepository, uow repositories.UnitOfWork) LairService {
+	return &lairService{lairRepo: lairRepo, decorationRepo: decorationRepo, uow: uow}
+}
+
+// in returns a copy of the service that works through repos
+func (s *lairService) in(repos repositories.Repositories) *lairService {
+	return &lairService{lairRepo: repos.Lairs, decorationRepo: repos.Decorations, uow: repos.Work}
+}
+
+// locked runs fn in a unit of work holding the user's lock, which guards
+// their set of layouts (how many there are and which is active)
+func (s *lairService) locked(userID uuid.UUID, fn func(tx *lairService) error) error {
+	return s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+		return fn(s.in(repos))
+	})
+}
+
+func (s *lairService) GetLayouts(userID uuid.UUID) (*models.LairResponse, error) {
+	layouts, err := s.lairRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LairResponse{Width: lairWidth, Height: lairHeight, Layouts: layouts}, nil
+}
+
+func (s *lairService) GetLayout(userID uuid.UUID, layoutID uuid.UUID) (*models.LairLayout, error) {
+	return s.findOwnedLayout(userID, layoutID)
+}
+
+// CreateLayout saves a new layout; the user's first layout becomes active
+func (s *lairService) CreateLayout(userID uuid.UUID, req models.LairLayoutRequest) (*models.LairLayout, error) {
+	layout := &models.LairLayout{
+		UserID:     userID,
+		Name:       strings.TrimSpace(req.Name),
+		Placements: lairPlacements(req.Placements),
+	}
+
+	err := s.locked(userID, func(tx *lairService) error {
+		layouts, err := tx.lairRepo.FindByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if len(layouts) >= maxLayoutsPerUser {
+			return fmt.Errorf("you can keep at most %d layouts", maxLayoutsPerUser)
+		}
+
+		layout.Active = len(layouts) == 0
+		if err := checkLayoutName(layouts, layout); err != nil {
+			return err
+		}
+		if err := tx.checkPlacements(userID, layout.Placements); err != nil {
+			return err
+		}
+
+		return tx.lairRepo.Create(layout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+// SaveLayout renames the layout and replaces its placements
+func (s *lairService) SaveLayout(userID uuid.UUID, layoutID uuid.UUID, req models.LairLayoutRequest) (*models.LairLayout, error) {
+	var layout *models.LairLayout
+	err := s.locked(userID, func(tx *lairService) error {
+		var err error
+		if layout, err = tx.findOwnedLayout(userID, layoutID); err != nil {
+			return err
+		}
+
+		layouts, err := tx.lairRepo.FindByUserID(userID)
+		if err != nil {
+			return err
+		}
+
+		layout.Name = strings.TrimSpace(req.Name)
+		layout.Placements = lairPlacements(req.Placements)
+		if err := checkLayoutName(layouts, layout); err != nil {
+			return err
+		}
+
+		return tx.save(layout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+// DeleteLayout removes the layout; if it was active, the oldest remaining
+// layout takes its place
+func (s *lairService) DeleteLayout(userID uuid.UUID, layoutID uuid.UUID) error {
+	return s.locked(userID, func(tx *lairService) error {
+		layout, err := tx.findOwnedLayout(userID, layoutID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.lairRepo.Delete(layout.ID); err != nil {
+			return err
+		}
+
+		if !layout.Active {
+			return nil
+		}
+		layouts, err := tx.lairRepo.FindByUserID(userID)
+		if err != nil || len(layouts) == 0 {
+			return err
+		}
+		return tx.lairRepo.SetActive(userID, layouts[0].ID)
+	})
+}
+
+func (s *lairService) ActivateLayout(userID uuid.UUID, layoutID uuid.UUID) (*models.LairLayout, error) {
+	var layout *models.LairLayout
+	err := s.locked(userID, func(tx *lairService) error {
+		var err error
+		if layout, err = tx.findOwnedLayout(userID, layoutID); err != nil {
+			return err
+		}
+		return tx.lairRepo.SetActive(userID, layout.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	layout.Active = true
+	return layout, nil
+}
+
+// PlaceDecoration puts a decoration on the grid, or moves and rotates it
+// if it is already placed
+func (s *lairService) PlaceDecoration(userID uuid.UUID, layoutID uuid.UUID, req models.PlaceDecorationRequest) (*models.LairLayout, error) {
+	var layout *models.LairLayout
+	err := s.locked(userID, func(tx *lairService) error {
+		var err error
+		if layout, err = tx.findOwnedLayout(userID, layoutID); err != nil {
+			return err
+		}
+
+		placement := lairPlacements([]models.PlaceDecorationRequest{req})[0]
+		placed := false
+		for i := range layout.Placements {
+			if layout.Placements[i].Decoration == placement.Decoration {
+				layout.Placements[i] = placement
+				placed = true
+			}
+		}
+		if !placed {
+			layout.Placements = append(layout.Placements, placement)
+		}
+
+		return tx.save(layout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+func (s *lairService) RemoveDecoration(userID uuid.UUID, layoutID uuid.UUID, decoration string) (*models.LairLayout, error) {
+	var layout *models.LairLayout
+	err := s.locked(userID, func(tx *lairService) error {
+		var err error
+		if layout, err = tx.findOwnedLayout(userID, layoutID); err != nil {
+			return err
+		}
+
+		kept := layout.Placements[:0]
+		for _, p := range layout.Placements {
+			if p.Decoration != decoration {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == len(layout.Placements) {
+			return errors.New("decoration is not placed in this layout")
+		}
+		layout.Placements = kept
+
+		return tx.save(layout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+func (s *lairService) save(layout *models.LairLayout) error {
+	if err := s.checkPlacements(layout.UserID, layout.Placements); err != nil {
+		return err
+	}
+	return s.lairRepo.Save(layout)
+}
+
+func (s *lairService) findOwnedLayout(userID uuid.UUID, layoutID uuid.UUID) (*models.LairLayout, error) {
+	layout, err := s.lairRepo.FindByID(layoutID)
+	if err != nil {
+		return nil, errors.New("layout not found")
+	}
+
+	if layout.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return layout, nil
+}
+
+// checkPlacements enforces the lair rules: each decoration is an owned
+// catalog decoration placed at most once, lies within the grid, and doesn't
+// overlap another decoration on the same layer
+func (s *lairService) checkPlacements(userID uuid.UUID, placements []models.LairPlacement) error {
+	owned, err := s.decorationRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	isOwned := make(map[string]bool, len(owned))
+	for _, d := range owned {
+		isOwned[d.Decoration] = true
+	}
+
+	type cell struct{ x, y int }
+	occupied := make(map[string]map[cell]string)
+	placed := make(map[string]bool, len(placements))
+
+	for _, p := range placements {
+		decoration, ok := catalog.FindDecoration(p.Decoration)
+		if !ok {
+			return fmt.Errorf("%s can't be placed in the lair", p.Decoration)
+		}
+		if !isOwned[decoration.ID] {
+			return fmt.Errorf("you don't own %s", decoration.Name)
+		}
+		if placed[decoration.ID] {
+			return fmt.Errorf("%s is placed more than once", decoration.Name)
+		}
+		placed[decoration.ID] = true
+
+		switch p.Rotation {
+		case 0, 90, 180, 270:
+		default:
+			return errors.New("rotation must be 0, 90, 180 or 270")
+		}
+
+		width, height := decoration.Footprint(p.Rotation)
+		if p.X < 0 || p.Y < 0 || p.X+width > lairWidth || p.Y+height > lairHeight {
+			return fmt.Errorf("%s doesn't fit on the grid there", decoration.Name)
+		}
+
+		cells := occupied[decoration.Layer]
+		if cells == nil {
+			cells = make(map[cell]string)
+			occupied[decoration.Layer] = cells
+		}
+		for x := p.X; x < p.X+width; x++ {
+			for y := p.Y; y < p.Y+height; y++ {
+				if other, taken := cells[cell{x, y}]; taken {
+					return fmt.Errorf("%s overlaps %s", decoration.Name, other)
+				}
+				cells[cell{x, y}] = decoration.Name
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLayoutName rejects blank names and names another of the user's
+// layouts already uses
+func checkLayoutName(layouts []models.LairLayout, layout *models.LairLayout) error {
+	if layout.Name == "" {
+		return errors.New("name is required")
+	}
+	for _, other := range layouts {
+		if other.ID != layout.ID && strings.EqualFold(other.Name, layout.Name) {
+			return errors.New("you already have a layout with this name")
+		}
+	}
+	return nil
+}
+
+func lairPlacements(reqs []models.PlaceDecorationRequest) []models.LairPlacement {
+	placements := make([]models.LairPlacement, len(reqs))
+	for i, req := range reqs {
+		placements[i] = models.LairPlacement{
+			Decoration: req.Decoration,
+			X:          req.X,
+			Y:          req.Y,
+			Rotation:   req.Rotation,
+		}
+	}
+	return placements
+}
+
+// internal/services/ledger_service.go
+
+type LedgerService interface {
+	GetTransactions(userID uuid.UUID, before time.Time, limit int) ([]models.GoldTransaction, error)
+}
+
+type ledgerService struct {
+	ledgerRepo repositories.LedgerRepository
+}
+
+func NewLedgerService(ledgerRepo repositories.LedgerRepository) LedgerService {
+	return &ledgerService{ledgerRepo: ledgerRepo}
+}
+
+// GetTransactions pages through the user's ledger, newest first; a zero
+// before starts from the latest entry
+func (s *ledgerService) GetTransactions(userID uuid.UUID, before time.Time, limit int) ([]models.GoldTransaction, error) {
+	if before.IsZero() {
+		before = time.Now().Add(time.Second)
+	}
+	return s.ledgerRepo.FindByUserID(userID, before, limit)
+}
+
+// internal/services/achievement_service.go
+
+// Domain events services publish to the achievement engine
+const (
+	EventQuestCompleted   = "quest_completed"
+	EventPetProgressed    = "pet_progressed"
+	EventDecorationBought = "decoration_bought"
+	EventItemCrafted      = "item_crafted"
+	EventGiftAccepted     = "gift_accepted"
+)
+
+// achievementTriggers lists the metrics each event can move
+var achievementTriggers = map[string][]string{
+	EventQuestCompleted:   {catalog.MetricQuestsCompleted, catalog.MetricQuestStreak},
+	EventPetProgressed:    {catalog.MetricPetLevel},
+	EventDecorationBought: {catalog.MetricDecorationsOwned},
+	EventItemCrafted:      {catalog.MetricDecorationsOwned},
+	EventGiftAccepted:     {catalog.MetricDecorationsOwned},
+}
+
+type AchievementService interface {
+	GetAchievements(userID uuid.UUID) ([]models.AchievementResponse, error)
+}
+
+type achievementService struct {
+	uow repositories.UnitOfWork
+}
+
+func NewAchievementService(uow repositories.UnitOfWork) AchievementService {
+	return &achievementService{uow: uow}
+}
+
+// GetAchievements lists every achievement with the user's progress. It
+// only reads: achievements unlock when a service publishes an event.
+func (s *achievementService) GetAchievements(userID uuid.UUID) ([]models.AchievementResponse, error) {
+	all := catalog.AllAchievements()
+
+	var metrics []string
+	for _, a := range all {
+		metrics = append(metrics, a.Metric)
+	}
+
+	var progress map[string]int
+	var unlocked []models.UserAchievement
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		if progress, err = (achievementEngine{repos: repos}).measureAll(userID, metrics); err != nil {
+			return err
+		}
+		unlocked, err = repos.Achievements.FindByUserID(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, u := range unlocked {
+		unlockedAt[u.AchievementID] = u.UnlockedAt
+	}
+
+	response := make([]models.AchievementResponse, len(all))
+	for i, a := range all {
+		response[i] = models.AchievementResponse{
+			ID:          a.ID,
+			Name:        a.Name,
+			Description: a.Description,
+			Progress:    clamp(progress[a.Metric], 0, a.Goal),
+			Goal:        a.Goal,
+			RewardGold:  a.Reward.Gold,
+			RewardItems: a.Reward.Items,
+		}
+		if at, ok := unlockedAt[a.ID]; ok {
+			response[i].UnlockedAt = &at
+			response[i].Progress = a.Goal
+		}
+	}
+
+	return response, nil
+}
+
+// achievementEngine evaluates the achievement rules when a service
+// publishes a domain event, unlocking and paying out every achievement the
+// user has reached. It works inside the publisher's unit of work.
+type achievementEngine struct {
+	repos repositories.Repositories
+}
+
+func (e achievementEngine) publish(userID uuid.UUID, event string) error {
+	_, err := e.evaluate(userID, achievementTriggers[event])
+	return err
+}
+
+// evaluate measures the metrics, unlocks the achievements they complete
+// and returns the measurements
+func (e achievementEngine) evaluate(userID uuid.UUID, metrics []string) (map[string]int, error) {
+	progress, err := e.measureAll(userID, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := e.repos.Achievements.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	have := make(map[string]bool, len(unlocked))
+	for _, u := range unlocked {
+		have[u.AchievementID] = true
+	}
+
+	now := time.Now()
+	for _, a := range catalog.AllAchievements() {
+		value, measured := progress[a.Metric]
+		if !measured || value < a.Goal || have[a.ID] {
+			continue
+		}
+		if err := e.unlock(userID, a, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return progress, nil
+}
+
+// measureAll measures each of the metrics once
+func (e achievementEngine) measureAll(userID uuid.UUID, metrics []string) (map[string]int, error) {
+	progress := make(map[string]int, len(metrics))
+	for _, metric := range metrics {
+		if _, done := progress[metric]; done {
+			continue
+		}
+		value, err := e.measure(userID, metric)
+		if err != nil {
+			return nil, err
+		}
+		progress[metric] = value
+	}
+	return progress, nil
+}
+
+func (e achievementEngine) measure(userID uuid.UUID, metric string) (int, error) {
+	switch metric {
+	case catalog.MetricQuestsCompleted:
+		count, err := e.repos.Tasks.CountCompleted(userID)
+		return int(count), err
+	case catalog.MetricQuestStreak:
+		streak, err := e.repos.Streaks.FindByUserID(userID)
+		if err != nil || streak == nil {
+			return 0, err
+		}
+		return streak.Current, nil
+	case catalog.MetricPetLevel:
+		return e.repos.Pets.MaxLevelByUserID(userID)
+	case catalog.MetricDecorationsOwned:
+		count, err := e.repos.Decorations.CountByUserID(userID)
+		return int(count), err
+	}
+	return 0, fmt.Errorf("unknown achievement metric %q", metric)
+}
+
+// unlock records the achievement and pays out its reward, once
+func (e achievementEngine) unlock(userID uuid.UUID, achievement catalog.Achievement, at time.Time) error {
+	unlocked, err := e.repos.Achievements.Unlock(&models.UserAchievement{
+		UserID:        userID,
+		AchievementID: achievement.ID,
+		UnlockedAt:    at,
+	})
+	if err != nil || !unlocked {
+		return err
+	}
+
+	if achievement.Reward.Gold > 0 {
+		key := "achievement:" + achievement.ID
+		if _, err := e.repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:         userID,
+			Amount:         achievement.Reward.Gold,
+			Reason:         models.GoldAchievement,
+			RefType:        "achievement",
+			RefID:          achievement.ID,
+			IdempotencyKey: &key,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for itemID, quantity := range achievement.Reward.Items {
+		item, ok := catalog.FindItem(itemID)
+		if !ok {
+			return fmt.Errorf("unknown reward item %q", itemID)
+		}
+		if err := e.rewardItem(userID, achievement, item, quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewardItem adds a reward item under the shop's one-of-each-toy rule:
+// toys past the one the user may own are paid out at their shop price
+func (e achievementEngine) rewardItem(userID uuid.UUID, achievement catalog.Achievement, item catalog.Item, quantity int) error {
+	if item.Kind == catalog.ItemToy {
+		owned, err := e.repos.Inventory.Quantity(userID, item.ID)
+		if err != nil {
+			return err
+		}
+		kept := min(quantity, max(1-owned, 0))
+		if surplus := quantity - kept; surplus > 0 {
+			key := "achievement:" + achievement.ID + ":" + item.ID
+			if _, err := e.repos.Ledger.Apply(&models.GoldTransaction{
+				UserID:         userID,
+				Amount:         surplus * item.Price,
+				Reason:         models.GoldAchievement,
+				RefType:        "achievement",
+				RefID:          achievement.ID,
+				IdempotencyKey: &key,
+			}); err != nil {
+				return err
+			}
+		}
+		if quantity = kept; quantity == 0 {
+			return nil
+		}
+	}
+
+	return e.repos.Inventory.Add(userID, item.ID, quantity)
+}
+
+// internal/services/streak_service.go
+
+// dayLayout is how streak days are written
+const dayLayout = "2006-01-02"
+
+// streakLookback bounds how much quest history seeds a new streak
+const streakLookback = 366 * 24 * time.Hour
+
+// streakMilestones pay an escalating bonus each time a streak reaches them
+var streakMilestones = []models.StreakMilestone{
+	{Days: 3, Gold: 10},
+	{Days: 7, Gold: 30},
+	{Days: 14, Gold: 75},
+	{Days: 30, Gold: 200},
+	{Days: 60, Gold: 400},
+	{Days: 100, Gold: 750},
+	{Days: 365, Gold: 3000},
+}
+
+// StreakConfig prices the daily check-in and streak freezes
+type StreakConfig struct {
+	CheckInGold int
+	FreezePrice int
+	MaxFreezes  int
+}
+
+func NewStreakConfig(cfg *config.Config) StreakConfig {
+	return StreakConfig{
+		CheckInGold: cfg.StreakCheckInGold,
+		FreezePrice: cfg.StreakFreezePrice,
+		MaxFreezes:  cfg.StreakMaxFreezes,
+	}
+}
+
+type StreakService interface {
+	GetStreak(userID uuid.UUID) (*models.StreakResponse, error)
+	CheckIn(userID uuid.UUID) (*models.CheckInResponse, error)
+	BuyFreeze(userID uuid.UUID, idempotencyKey string) (*models.StreakResponse, error)
+}
+
+type streakService struct {
+	uow repositories.UnitOfWork
+	cfg StreakConfig
+}
+
+func NewStreakService(uow repositories.UnitOfWork, cfg StreakConfig) StreakService {
+	return &streakService{uow: uow, cfg: cfg}
+}
+
+func (s *streakService) GetStreak(userID uuid.UUID) (*models.StreakResponse, error) {
+	now := time.Now()
+
+	var response *models.StreakResponse
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		tracker := streakTracker{repos: repos}
+		loc, err := tracker.location(userID)
+		if err != nil {
+			return err
+		}
+		streak, err := tracker.lock(userID, loc, now)
+		if err != nil {
+			return err
+		}
+		response = s.response(streak, loc, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CheckIn pays the daily check-in reward, once per day in the user's zone
+func (s *streakService) CheckIn(userID uuid.UUID) (*models.CheckInResponse, error) {
+	now := time.Now()
+
+	var response *models.CheckInResponse
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		tracker := streakTracker{repos: repos}
+		loc, err := tracker.location(userID)
+		if err != nil {
+			return err
+		}
+		today := now.In(loc).Format(dayLayout)
+
+		key := "checkin:" + today
+		entry := &models.GoldTransaction{
+			UserID:         userID,
+			Amount:         s.cfg.CheckInGold,
+			Reason:         models.GoldCheckIn,
+			RefType:        "streak",
+			RefID:          today,
+			IdempotencyKey: &key,
+		}
+		applied, err := repos.Ledger.Apply(entry)
+		if err != nil {
+			return err
+		}
+
+		streak, err := tracker.lock(userID, loc, now)
+		if err != nil {
+			return err
+		}
+		// Moving to an earlier time zone must not make today new again
+		if !applied || streak.LastCheckIn >= today {
+			return errors.New("already checked in today")
+		}
+
+		streak.LastCheckIn = today
+		if err := repos.Streaks.Save(streak); err != nil {
+			return err
+		}
+
+		response = &models.CheckInResponse{
+			Gold:    entry.Amount,
+			Balance: entry.Balance,
+			Streak:  s.response(streak, loc, now),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// BuyFreeze buys a streak freeze, which is used up automatically to cover
+// a day without a completed quest
+func (s *streakService) BuyFreeze(userID uuid.UUID, idempotencyKey string) (*models.StreakResponse, error) {
+	now := time.Now()
+	limit := fmt.Errorf("you can hold at most %d streak freezes", s.cfg.MaxFreezes)
+
+	var response *models.StreakResponse
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		// Checked again under the lock; this one spares a doomed charge
+		current, err := repos.Streaks.FindByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if current != nil && current.Freezes >= s.cfg.MaxFreezes {
+			return limit
+		}
+
+		entry := &models.GoldTransaction{
+			UserID:  userID,
+			Amount:  -s.cfg.FreezePrice,
+			Reason:  models.GoldStreakFreeze,
+			RefType: "streak",
+		}
+		if idempotencyKey != "" {
+			key := "freeze:" + idempotencyKey
+			entry.IdempotencyKey = &key
+		}
+		applied, err := repos.Ledger.Apply(entry)
+		if err != nil {
+			return err
+		}
+
+		tracker := streakTracker{repos: repos}
+		loc, err := tracker.location(userID)
+		if err != nil {
+			return err
+		}
+		streak, err := tracker.lock(userID, loc, now)
+		if err != nil {
+			return err
+		}
+
+		if applied {
+			if streak.Freezes >= s.cfg.MaxFreezes {
+				return limit
+			}
+			streak.Freezes++
+			if err := repos.Streaks.Save(streak); err != nil {
+				return err
+			}
+		}
+
+		response = s.response(streak, loc, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *streakService) response(streak *models.Streak, loc *time.Location, now time.Time) *models.StreakResponse {
+	today := now.In(loc).Format(dayLayout)
+
+	response := &models.StreakResponse{
+		Current:        streak.Current,
+		Longest:        streak.Longest,
+		CompletedToday: streak.Current > 0 && streak.LastDay == today,
+		Freezes:        streak.Freezes,
+		MaxFreezes:     s.cfg.MaxFreezes,
+		FreezePrice:    s.cfg.FreezePrice,
+		CheckedInToday: streak.LastCheckIn == today,
+		CheckInGold:    s.cfg.CheckInGold,
+		TimeZone:       loc.String(),
+	}
+	for _, m := range streakMilestones {
+		if m.Days > streak.Current {
+			milestone := m
+			response.NextMilestone = &milestone
+			break
+		}
+	}
+
+	return response
+}
+
+// streakTracker keeps streaks current: it carries them over the days since
+// they were last extended and extends them as quests are completed. It
+// works inside the caller's unit of work.
+type streakTracker struct {
+	repos repositories.Repositories
+}
+
+// location is the user's time zone
+func (t streakTracker) location(userID uuid.UUID) (*time.Location, error) {
+	user, err := t.repos.Users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		// Zones are checked when set; one dropped from tzdata falls back
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// lock loads and locks the user's streak, brought up to date with now
+func (t streakTracker) lock(userID uuid.UUID, loc *time.Location, now time.Time) (*models.Streak, error) {
+	streak, err := t.repos.Streaks.Lock(userID)
+	if err != nil {
+		return nil, err
+	}
+	before := *streak
+
+	if streak.LastDay == "" {
+		if err := t.seed(streak, loc, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.settle(streak, loc, now); err != nil {
+		return nil, err
+	}
+
+	if *streak != before {
+		if err := t.repos.Streaks.Save(streak); err != nil {
+			return nil, err
+		}
+	}
+	return streak, nil
+}
+
+// record extends the streak for a quest completed at now, paying the
+// bonus of any milestone it reaches
+func (t streakTracker) record(userID uuid.UUID, now time.Time) error {
+	loc, err := t.location(userID)
+	if err != nil {
+		return err
+	}
+
+	streak, err := t.lock(userID, loc, now)
+	if err != nil {
+		return err
+	}
+
+	today := now.In(loc).Format(dayLayout)
+	if streak.LastDay == today {
+		return nil
+	}
+
+	streak.Current++
+	streak.Longest = max(streak.Longest, streak.Current)
+	streak.LastDay = today
+	if err := t.repos.Streaks.Save(streak); err != nil {
+		return err
+	}
+
+	for _, m := range streakMilestones {
+		if m.Days != streak.Current {
+			continue
+		}
+		key := "streak:" + today
+		_, err := t.repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:         userID,
+			Amount:         m.Gold,
+			Reason:         models.GoldStreakBonus,
+			RefType:        "streak",
+			RefID:          strconv.Itoa(m.Days),
+			IdempotencyKey: &key,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// seed starts a streak the user has never had tracked from their quest
+// history, so streaks built before tracking aren't lost
+func (t streakTracker) seed(streak *models.Streak, loc *time.Location, now time.Time) error {
+	completions, err := t.repos.Tasks.FindCompletionTimes(streak.UserID, now.Add(-streakLookback))
+	if err != nil {
+		return err
+	}
+
+	days := make(map[string]bool, len(completions))
+	for _, c := range completions {
+		days[c.In(loc).Format(dayLayout)] = true
+	}
+
+	// A streak last extended yesterday is still alive until today ends
+	day := startOfDay(now, loc)
+	if !days[day.Format(dayLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	last := day.Format(dayLayout)
+
+	run := 0
+	for days[day.Format(dayLayout)] {
+		run++
+		day = day.AddDate(0, 0, -1)
+	}
+	if run > 0 {
+		streak.Current, streak.Longest, streak.LastDay = run, run, last
+	}
+	return nil
+}
+
+// settle carries the streak over the days missed since its last day, up
+// to yesterday: days on vacation are skipped and each other day takes a
+// freeze. Without enough freezes the streak breaks and keeps them.
+func (t streakTracker) settle(streak *models.Streak, loc *time.Location, now time.Time) error {
+	if streak.Current == 0 {
+		return nil
+	}
+
+	last, err := time.ParseInLocation(dayLayout, streak.LastDay, loc)
+	if err != nil {
+		return err
+	}
+	first := last.AddDate(0, 0, 1)
+	today := startOfDay(now, loc)
+	if !first.Before(today) {
+		return nil
+	}
+
+	vacations, err := t.repos.Vacations.FindOverlapping(streak.UserID, first, today)
+	if err != nil {
+		return err
+	}
+
+	missed := 0
+	for day := first; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if onVacation(vacations, day, day.AddDate(0, 0, 1)) {
+			continue
+		}
+		if missed++; missed > streak.Freezes {
+			streak.Current = 0
+			return nil
+		}
+	}
+
+	streak.Freezes -= missed
+	streak.LastDay = today.AddDate(0, 0, -1).Format(dayLayout)
+	return nil
+}
+
+// onVacation reports whether any of the vacations overlaps [from, to)
+func onVacation(vacations []models.Vacation, from, to time.Time) bool {
+	for i := range vacations {
+		if vacations[i].StartsAt.Before(to) && vacations[i].End().After(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// startOfDay is midnight at the start of t's day in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// internal/services/loot_service.go
+
+// LootRNG draws the random numbers behind loot drops. It is safe for
+// concurrent use, and a fixed seed makes a sequence of drops reproducible.
+type LootRNG struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewLootRNG seeds the RNG, from the clock if seed is 0
+func NewLootRNG(seed int64) *LootRNG {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &LootRNG{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (g *LootRNG) Float64() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rnd.Float64()
+}
+
+func (g *LootRNG) Intn(n int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rnd.Intn(n)
+}
+
+// lootRoller rolls the loot completed quests drop and pays it out
+type lootRoller struct {
+	repos repositories.Repositories
+	rng   *LootRNG
+}
+
+// in returns the roller working through repos
+func (l lootRoller) in(repos repositories.Repositories) lootRoller {
+	l.repos = repos
+	return l
+}
+
+// roll draws the quest's drop, if it drops anything, from the loot table
+// for its reward; bonus raises the tier's drop chance by that percentage.
+// A rare drop resets the user's pity timer.
+func (l lootRoller) roll(task *models.Task, bonus int) (*models.LootDrop, error) {
+	tier := catalog.LootTierFor(task.Reward)
+
+	pity, err := l.repos.Loot.Lock(task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	pity.SinceRare++
+
+	var drop *catalog.LootDrop
+	guaranteed := tier.PityAfter > 0 && pity.SinceRare >= tier.PityAfter
+	if guaranteed {
+		drop = l.pick(tier.Drops, true)
+	} else if l.rng.Float64() < tier.Chance*(1+float64(bonus)/100) {
+		drop = l.pick(tier.Drops, false)
+	}
+	if drop != nil && drop.Rare {
+		pity.SinceRare = 0
+	}
+
+	if err := l.repos.Loot.Save(pity); err != nil {
+		return nil, err
+	}
+	if drop == nil {
+		return nil, nil
+	}
+
+	if drop.Gold > 0 {
+		key := "loot:" + task.ID.String()
+		if _, err := l.repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:         task.UserID,
+			Amount:         drop.Gold,
+			Reason:         models.GoldLoot,
+			RefType:        "task",
+			RefID:          task.ID.String(),
+			IdempotencyKey: &key,
+		}); err != nil {
+			return nil, err
+		}
+	} else if err := l.repos.Inventory.Add(task.UserID, drop.Item, drop.Quantity); err != nil {
+		return nil, err
+	}
+
+	return &models.LootDrop{
+		Tier:     tier.ID,
+		Item:     drop.Item,
+		Quantity: drop.Quantity,
+		Gold:     drop.Gold,
+		Rare:     drop.Rare,
+		Pity:     guaranteed,
+	}, nil
+}
+
+// pick draws one of the drops by weight, only among the rare ones if
+// rareOnly
+func (l lootRoller) pick(drops []catalog.LootDrop, rareOnly bool) *catalog.LootDrop {
+	total := 0
+	for _, d := range drops {
+		if d.Rare || !rareOnly {
+			total += d.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	n := l.rng.Intn(total)
+	for i := range drops {
+		if rareOnly && !drops[i].Rare {
+			continue
+		}
+		if n < drops[i].Weight {
+			return &drops[i]
+		}
+		n -= drops[i].Weight
+	}
+	return nil
+}
+
+// internal/services/crafting_service.go
+
+type CraftingService interface {
+	GetRecipes(userID uuid.UUID) ([]models.RecipeResponse, error)
+	Craft(userID uuid.UUID, recipeID string, idempotencyKey string) (*models.CraftResponse, error)
+}
+
+type craftingService struct {
+	uow repositories.UnitOfWork
+}
+
+func NewCraftingService(uow repositories.UnitOfWork) CraftingService {
+	return &craftingService{uow: uow}
+}
+
+// GetRecipes lists every recipe with whether the user has unlocked it and
+// can afford it right now
+func (s *craftingService) GetRecipes(userID uuid.UUID) ([]models.RecipeResponse, error) {
+	all := catalog.AllRecipes()
+	response := make([]models.RecipeResponse, len(all))
+
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		unlocks, err := loadRecipeUnlocks(repos, userID)
+		if err != nil {
+			return err
+		}
+
+		gold, err := repos.Users.GetGold(userID)
+		if err != nil {
+			return err
+		}
+		owned, err := repos.Inventory.FindByUserID(userID)
+		if err != nil {
+			return err
+		}
+		have := make(map[string]int, len(owned))
+		for _, item := range owned {
+			have[item.ItemID] = item.Quantity
+		}
+
+		for i, recipe := range all {
+			unlocked := unlocks.check(recipe) == nil
+			canCraft := unlocked && gold >= recipe.Gold
+			for id, quantity := range recipe.Items {
+				canCraft = canCraft && have[id] >= quantity
+			}
+			if recipe.Output.Decoration != "" {
+				owned, err := repos.Decorations.Exists(userID, recipe.Output.Decoration)
+				if err != nil {
+					return err
+				}
+				canCraft = canCraft && !owned
+			}
+
+			response[i] = models.RecipeResponse{
+				ID:          recipe.ID,
+				Name:        recipe.Name,
+				Description: recipe.Description,
+				Items:       recipe.Items,
+				Gold:        recipe.Gold,
+				Item:        recipe.Output.Item,
+				Decoration:  recipe.Output.Decoration,
+				Quantity:    recipe.Output.Quantity,
+				MinLevel:    recipe.MinLevel,
+				Achievement: recipe.Achievement,
+				Unlocked:    unlocked,
+				CanCraft:    canCraft,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Craft uses up the recipe's items and fee and hands out its output, all
+// or nothing. Retries with the same idempotency key don't craft again.
+func (s *craftingService) Craft(userID uuid.UUID, recipeID string, idempotencyKey string) (*models.CraftResponse, error) {
+	recipe, ok := catalog.FindRecipe(recipeID)
+	if !ok {
+		return nil, errors.New("recipe not found")
+	}
+
+	response := &models.CraftResponse{
+		Recipe:     recipe.ID,
+		Item:       recipe.Output.Item,
+		Decoration: recipe.Output.Decoration,
+		Quantity:   recipe.Output.Quantity,
+	}
+
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		unlocks, err := loadRecipeUnlocks(repos, userID)
+		if err != nil {
+			return err
+		}
+		if err := unlocks.check(recipe); err != nil {
+			return err
+		}
+
+		if recipe.Output.Decoration != "" {
+			if _, ok := catalog.FindDecoration(recipe.Output.Decoration); !ok {
+				return errors.New("recipe not available")
+			}
+			owned, err := repos.Decorations.Exists(userID, recipe.Output.Decoration)
+			if err != nil {
+				return err
+			}
+			if owned {
+				return errors.New("decoration already owned")
+			}
+		}
+
+		entry := &models.GoldTransaction{
+			UserID:  userID,
+			Amount:  -recipe.Gold,
+			Reason:  models.GoldCrafting,
+			RefType: "recipe",
+			RefID:   recipe.ID,
+		}
+		if idempotencyKey != "" {
+			// Scoped to the recipe, so reusing a key for another recipe crafts it
+			key := "craft:" + recipe.ID + ":" + idempotencyKey
+			entry.IdempotencyKey = &key
+		}
+		applied, err := repos.Ledger.Apply(entry)
+		if err != nil {
+			return err
+		}
+		response.Balance = entry.Balance
+		if !applied {
+			return nil
+		}
+
+		// Sorted, so concurrent crafts lock inventory rows in the same order
+		ids := make([]string, 0, len(recipe.Items))
+		for id := range recipe.Items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			removed, err := repos.Inventory.Remove(userID, id, recipe.Items[id])
+			if err != nil {
+				return err
+			}
+			if !removed {
+				item, _ := catalog.FindItem(id)
+				return fmt.Errorf("not enough %s", item.Name)
+			}
+		}
+
+		if recipe.Output.Item != "" {
+			return repos.Inventory.Add(userID, recipe.Output.Item, recipe.Output.Quantity)
+		}
+
+		if err := repos.Decorations.Create(&models.Decoration{
+			UserID:     userID,
+			Decoration: recipe.Output.Decoration,
+		}); err != nil {
+			return err
+		}
+		return achievementEngine{repos: repos}.publish(userID, EventItemCrafted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// recipeUnlocks is what the user has done toward unlocking recipes
+type recipeUnlocks struct {
+	level        int
+	achievements map[string]bool
+}
+
+func loadRecipeUnlocks(repos repositories.Repositories, userID uuid.UUID) (recipeUnlocks, error) {
+	level, err := repos.Pets.MaxLevelByUserID(userID)
+	if err != nil {
+		return recipeUnlocks{}, err
+	}
+
+	unlocked, err := repos.Achievements.FindByUserID(userID)
+	if err != nil {
+		return recipeUnlocks{}, err
+	}
+	achievements := make(map[string]bool, len(unlocked))
+	for _, a := range unlocked {
+		achievements[a.AchievementID] = true
+	}
+
+	return recipeUnlocks{level: level, achievements: achievements}, nil
+}
+
+// check explains why the recipe is still locked, or returns nil
+func (u recipeUnlocks) check(recipe catalog.Recipe) error {
+	if u.level < recipe.MinLevel {
+		return fmt.Errorf("recipe requires a level %d pet", recipe.MinLevel)
+	}
+	if recipe.Achievement != "" && !u.achievements[recipe.Achievement] {
+		achievement, _ := catalog.FindAchievement(recipe.Achievement)
+		return fmt.Errorf("recipe requires the %s achievement", achievement.Name)
+	}
+	return nil
+}
+
+// internal/services/character_service.go
+
+const (
+	// Leaving character level L takes characterBaseExp * L XP
+	characterBaseExp = 100
+	// Quests earn one XP per gold of reward, and at least characterMinExp
+	characterMinExp = 5
+	// Every attributePointsPerPercent points of an attribute add 1% to its
+	// bonus, up to attributeBonusCap percent
+	attributePointsPerPercent = 2
+	attributeBonusCap         = 25
+)
+
+type CharacterService interface {
+	GetCharacter(userID uuid.UUID) (*models.CharacterResponse, error)
+}
+
+type characterService struct {
+	characterRepo repositories.CharacterRepository
+}
+
+func NewCharacterService(characterRepo repositories.CharacterRepository) CharacterService {
+	return &characterService{characterRepo: characterRepo}
+}
+
+func (s *characterService) GetCharacter(userID uuid.UUID) (*models.CharacterResponse, error) {
+	character, err := findCharacter(s.characterRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	return characterResponse(character), nil
+}
+
+// findCharacter loads the user's character; one who hasn't completed a
+// quest since characters were added is still a fresh level 1
+func findCharacter(characterRepo repositories.CharacterRepository, userID uuid.UUID) (*models.Character, error) {
+	character, err := characterRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		character = &models.Character{UserID: userID, Level: 1}
+	}
+	return character, nil
+}
+
+func characterResponse(character *models.Character) *models.CharacterResponse {
+	return &models.CharacterResponse{
+		Character:      *character,
+		ExpToNextLevel: characterExpToNextLevel(character.Level),
+		Bonuses:        characterBonuses(character),
+	}
+}
+
+func characterExpToNextLevel(level int) int {
+	return characterBaseExp * level
+}
+
+func characterBonuses(character *models.Character) models.CharacterBonuses {
+	return models.CharacterBonuses{
+		Gold:         attributeBonus(character.Strength),
+		PetExp:       attributeBonus(character.Intelligence),
+		PetHappiness: attributeBonus(character.Constitution),
+		Loot:         attributeBonus(character.Perception),
+	}
+}
+
+func attributeBonus(points int) int {
+	return min(points/attributePointsPerPercent, attributeBonusCap)
+}
+
+// advanceCharacter credits a completed quest to the character: XP for its
+// reward, carried across as many level ups as it pays for, and the
+// attribute points of its category
+func advanceCharacter(character *models.Character, task *models.Task) {
+	character.Exp += max(task.Reward, characterMinExp)
+	for character.Exp >= characterExpToNextLevel(character.Level) {
+		character.Exp -= characterExpToNextLevel(character.Level)
+		character.Level++
+	}
+
+	category, ok := catalog.FindCategory(task.Category)
+	if !ok {
+		return
+	}
+	for attr, points := range category.Attributes {
+		switch attr {
+		case catalog.AttrStrength:
+			character.Strength += points
+		case catalog.AttrIntelligence:
+			character.Intelligence += points
+		case catalog.AttrConstitution:
+			character.Constitution += points
+		case catalog.AttrPerception:
+			character.Perception += points
+		}
+	}
+}
+
+// internal/services/gift_service.go
+
+// sentGiftsShown is how many sent gifts GetSentGifts lists
+const sentGiftsShown = 50
+
+// ErrGiftKeyReused is returned when a gift's idempotency key was already
+// used for a different gift
+var ErrGiftKeyReused = errors.New("idempotency key already used for a different gift")
+
+type GiftService interface {
+	SendGift(senderID uuid.UUID, req models.SendGiftRequest, idempotencyKey string) (*models.Gift, error)
+	GetInbox(userID uuid.UUID) ([]models.Gift, error)
+	GetSentGifts(userID uuid.UUID) ([]models.Gift, error)
+	AcceptGift(userID uuid.UUID, giftID uuid.UUID) (*models.Gift, error)
+	DeclineGift(userID uuid.UUID, giftID uuid.UUID) (*models.Gift, error)
+}
+
+type giftService struct {
+	giftRepo   repositories.GiftRepository
+	userRepo   repositories.UserRepository
+	uow        repositories.UnitOfWork
+	perDay     int
+	goldPerDay int
+}
+
+func NewGiftService(giftRepo repositories.GiftRepository, userRepo repositories.UserRepository, uow repositories.UnitOfWork, perDay, goldPerDay int) GiftService {
+	return &giftService{giftRepo: giftRepo, userRepo: userRepo, uow: uow, perDay: perDay, goldPerDay: goldPerDay}
+}
+
+// SendGift takes what the gift carries from the sender and leaves it in
+// the recipient's inbox. Retries with the same idempotency key return the
+// gift already sent.
+//
+// Gifts were meant for friends and guildmates only, but there is no friend
+// or guild relationship to check yet, so any user can be sent a gift by
+// email and the daily limits are the only guard against laundering.
+func (s *giftService) SendGift(senderID uuid.UUID, req models.SendGiftRequest, idempotencyKey string) (*models.Gift, error) {
+	if req.Gold == 0 && req.ItemID == "" && req.Decoration == "" {
+		return nil, errors.New("a gift needs gold, an item or a decoration")
+	}
+
+	quantity := 0
+	if req.ItemID != "" {
+		if _, ok := catalog.FindItem(req.ItemID); !ok {
+			return nil, errors.New("item not found")
+		}
+		quantity = max(req.Quantity, 1)
+	}
+	if req.Decoration != "" {
+		if _, ok := catalog.FindDecoration(req.Decoration); !ok {
+			return nil, errors.New("only lair decorations can be gifted")
+		}
+	}
+
+	sender, err := s.userRepo.FindByID(senderID)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := s.userRepo.FindByEmail(req.RecipientEmail)
+	if err != nil {
+		return nil, errors.New("recipient not found")
+	}
+	if recipient.ID == senderID {
+		return nil, errors.New("you cannot send a gift to yourself")
+	}
+
+	gift := &models.Gift{
+		ID:             uuid.New(),
+		SenderID:       senderID,
+		SenderEmail:    sender.Email,
+		RecipientID:    recipient.ID,
+		RecipientEmail: recipient.Email,
+		Gold:           req.Gold,
+		ItemID:         req.ItemID,
+		Quantity:       quantity,
+		Decoration:     req.Decoration,
+		Message:        req.Message,
+		Status:         models.GiftPending,
+	}
+	if idempotencyKey != "" {
+		gift.IdempotencyKey = &idempotencyKey
+	}
+
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		// Holding both users' locks, concurrent gifts from the sender or to
+		// the recipient can't both slip under the limits checked below
+		if err := repos.Users.Lock(senderID, recipient.ID); err != nil {
+			return err
+		}
+
+		// The key is stored on the gift itself, so retries of any kind of
+		// gift find it; the sender's lock keeps the lookup race-free
+		if idempotencyKey != "" {
+			sent, err := repos.Gifts.FindByIdempotencyKey(senderID, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			if sent != nil {
+				if sent.RecipientID != gift.RecipientID || sent.Gold != gift.Gold || sent.ItemID != gift.ItemID ||
+					sent.Quantity != gift.Quantity || sent.Decoration != gift.Decoration {
+					return ErrGiftKeyReused
+				}
+				gift = sent
+				return nil
+			}
+		}
+
+		if err := s.checkLimits(repos, gift, time.Now()); err != nil {
+			return err
+		}
+
+		if gift.Gold > 0 {
+			if _, err := repos.Ledger.Apply(&models.GoldTransaction{
+				UserID:  senderID,
+				Amount:  -gift.Gold,
+				Reason:  models.GoldGiftSent,
+				RefType: "gift",
+				RefID:   gift.ID.String(),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if gift.ItemID != "" {
+			removed, err := repos.Inventory.Remove(senderID, gift.ItemID, gift.Quantity)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				item, _ := catalog.FindItem(gift.ItemID)
+				return fmt.Errorf("not enough %s", item.Name)
+			}
+		}
+
+		if gift.Decoration != "" {
+			removed, err := repos.Decorations.Delete(senderID, gift.Decoration)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				return errors.New("decoration not owned")
+			}
+			if err := repos.Lairs.Unplace(senderID, gift.Decoration); err != nil {
+				return err
+			}
+		}
+
+		return repos.Gifts.Create(gift)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gift, nil
+}
+
+// checkLimits caps the gifts and gold a user sends, and the gold a user is
+// sent, in any 24 hours, so gifts can't launder gold between accounts. The
+// caller holds the sender's and recipient's locks.
+func (s *giftService) checkLimits(repos repositories.Repositories, gift *models.Gift, now time.Time) error {
+	since := now.Add(-24 * time.Hour)
+
+	count, gold, err := repos.Gifts.SentSince(gift.SenderID, since)
+	if err != nil {
+		return err
+	}
+	if count >= int64(s.perDay) {
+		return fmt.Errorf("you can send at most %d gifts a day", s.perDay)
+	}
+	if gold+gift.Gold > s.goldPerDay {
+		return fmt.Errorf("you can send at most %d gold in gifts a day", s.goldPerDay)
+	}
+
+	received, err := repos.Gifts.GoldReceivedSince(gift.RecipientID, since)
+	if err != nil {
+		return err
+	}
+	if received+gift.Gold > s.goldPerDay {
+		return errors.New("the recipient cannot be sent that much more gold today")
+	}
+
+	return nil
+}
+
+// GetInbox lists the gifts waiting for the user to accept or decline
+func (s *giftService) GetInbox(userID uuid.UUID) ([]models.Gift, error) {
+	return s.giftRepo.FindByRecipient(userID, models.GiftPending)
+}
+
+func (s *giftService) GetSentGifts(userID uuid.UUID) ([]models.Gift, error) {
+	return s.giftRepo.FindBySender(userID, sentGiftsShown)
+}
+
+func (s *giftService) AcceptGift(userID uuid.UUID, giftID uuid.UUID) (*models.Gift, error) {
+	return s.respond(userID, giftID, models.GiftAccepted)
+}
+
+// DeclineGift sends the gift back to its sender
+func (s *giftService) DeclineGift(userID uuid.UUID, giftID uuid.UUID) (*models.Gift, error) {
+	return s.respond(userID, giftID, models.GiftDeclined)
+}
+
+func (s *giftService) respond(userID uuid.UUID, giftID uuid.UUID, status string) (*models.Gift, error) {
+	var gift *models.Gift
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		gift, err = repos.Gifts.FindByID(giftID)
+		if err != nil {
+			return err
+		}
+		if gift == nil || gift.RecipientID != userID {
+			return errors.New("gift not found")
+		}
+
+		responded, err := repos.Gifts.Respond(gift, status, time.Now())
+		if err != nil {
+			return err
+		}
+		if !responded {
+			return errors.New("gift already answered")
+		}
+
+		if status == models.GiftDeclined {
+			return returnGift(repos, gift)
+		}
+		return acceptGift(repos, gift)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gift, nil
+}
+
+// acceptGift hands what the gift carries to its recipient. Toys and
+// decorations are owned once, so a gift of one the recipient already has
+// can only be declined.
+func acceptGift(repos repositories.Repositories, gift *models.Gift) error {
+	if gift.Gold > 0 {
+		key := "gift-received:" + gift.ID.String()
+		if _, err := repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:         gift.RecipientID,
+			Amount:         gift.Gold,
+			Reason:         models.GoldGiftReceived,
+			RefType:        "gift",
+			RefID:          gift.ID.String(),
+			IdempotencyKey: &key,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if gift.ItemID != "" {
+		if item, ok := catalog.FindItem(gift.ItemID); ok && !item.Consumable() {
+			owned, err := repos.Inventory.Quantity(gift.RecipientID, gift.ItemID)
+			if err != nil {
+				return err
+			}
+			if owned > 0 {
+				return fmt.Errorf("you already own %s", item.Name)
+			}
+		}
+		if err := repos.Inventory.Add(gift.RecipientID, gift.ItemID, gift.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if gift.Decoration != "" {
+		owned, err := repos.Decorations.Exists(gift.RecipientID, gift.Decoration)
+		if err != nil {
+			return err
+		}
+		if owned {
+			return errors.New("you already own that decoration")
+		}
+		if err := repos.Decorations.Create(&models.Decoration{
+			UserID:     gift.RecipientID,
+			Decoration: gift.Decoration,
+		}); err != nil {
+			return err
+		}
+		return achievementEngine{repos: repos}.publish(gift.RecipientID, EventGiftAccepted)
+	}
+
+	return nil
+}
+
+// returnGift gives a declined gift back to its sender
+func returnGift(repos repositories.Repositories, gift *models.Gift) error {
+	if gift.Gold > 0 {
+		key := "gift-returned:" + gift.ID.String()
+		if _, err := repos.Ledger.Apply(&models.GoldTransaction{
+			UserID:         gift.SenderID,
+			Amount:         gift.Gold,
+			Reason:         models.GoldGiftReturned,
+			RefType:        "gift",
+			RefID:          gift.ID.String(),
+			IdempotencyKey: &key,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if gift.ItemID != "" {
+		if err := repos.Inventory.Add(gift.SenderID, gift.ItemID, gift.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if gift.Decoration != "" {
+		// The sender may have bought it again meanwhile
+		owned, err := repos.Decorations.Exists(gift.SenderID, gift.Decoration)
+		if err != nil || owned {
+			return err
+		}
+		return repos.Decorations.Create(&models.Decoration{
+			UserID:     gift.SenderID,
+			Decoration: gift.Decoration,
+		})
+	}
+
+	return nil
+}
+
+// internal/services/sync_service.go
+
+type SyncService interface {
+	Sync(userID uuid.UUID, lastSyncAt time.Time) (*models.SyncResponse, error)
+}
+
+// syncEventLimit caps how many pet events one sync returns
+const syncEventLimit = 100
+
+type syncService struct {
+	taskRepo       repositories.TaskRepository
+	statusRepo     repositories.TaskStatusRepository
+	petRepo        repositories.PetRepository
+	decorationRepo repositories.DecorationRepository
+	lairRepo       repositories.LairRepository
+	characterRepo  repositories.CharacterRepository
+	pets           petClock
+}
+
+func NewSyncService(taskRepo repositories.TaskRepository, statusRepo repositories.TaskStatusRepository, petRepo repositories.PetRepository, decorationRepo repositories.DecorationRepository, lairRepo repositories.LairRepository, characterRepo repositories.CharacterRepository, vacationRepo repositories.VacationRepository, petCfg PetConfig) SyncService {
+	return &syncService{taskRepo: taskRepo, statusRepo: statusRepo, petRepo: petRepo, decorationRepo: decorationRepo, lairRepo: lairRepo, characterRepo: characterRepo, pets: petClock{petRepo: petRepo, vacationRepo: vacationRepo, cfg: petCfg}}
+}
+
+func (s *syncService) Sync(userID uuid.UUID, lastSyncAt time.Time) (*models.SyncResponse, error) {
+	tasks, err := s.taskRepo.FindUpdatedSince(userID, lastSyncAt)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.statusRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pets, err := s.pets.findAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pet *models.Pet
+	for i := range pets {
+		if pets[i].Active {
+			pet = &pets[i]
+		}
+	}
+
+	events, err := s.petRepo.FindEventsSince(userID, lastSyncAt, syncEventLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	decorations, err := s.decorationRepo.FindUpdatedSince(userID, lastSyncAt)
+	if err != nil {
+		return nil, err
+	}
+
+	lair, err := s.lairRepo.FindActive(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	character, err := findCharacter(s.characterRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SyncResponse{
+		Tasks:       tasks,
+		Statuses:    statuses,
+		Pet:         pet,
+		Pets:        pets,
+		PetEvents:   events,
+		Decorations: decorations,
+		Lair:        lair,
+		Character:   characterResponse(character),
+		SyncedAt:    time.Now(),
+	}, nil
+}
+
+// internal/services/invite_service.go
+
+type InviteService interface {
+	CreateInvite(email string) (*models.InviteResponse, error)
+	ValidateInvite(token string) (string, error)
+}
+
+type inviteService struct {
+	jwtSecret string
+	appURL    string
+}
+
+func NewInviteService(jwtSecret, appURL string) InviteService {
+	return &inviteService{jwtSecret: jwtSecret, appURL: appURL}
+}
+
+func (s *inviteService) CreateInvite(email string) (*models.InviteResponse, error) {
+	claims := jwt.MapClaims{
+		"email": email,
+		"exp":   time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"type":  "invite",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	inviteURL := s.appURL + "/invite/" + tokenString
+	qrCodeURL := s.appURL + "/api/v1/invite/" + tokenString + "/qr"
+
+	return &models.InviteResponse{
+		Token:     tokenString,
+		QRCodeURL: qrCodeURL,
+		InviteURL: inviteURL,
+	}, nil
+}
+
+func (s *inviteService) ValidateInvite(token string) (string, error) {
+	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	})
+
+	if err != nil || !parsedToken.Valid {
+		return "", errors.New("invalid invite token")
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errors.New("invalid token claims")
 	}
 
 	email, ok := claims["email"].(string)
@@ -506,7 +4731,464 @@ func (s *inviteService) ValidateInvite(token string) (string, error) {
 	return email, nil
 }
 
+// internal/services/time_tracking_service.go
+
+const (
+	defaultPomodoroMinutes = 25
+	focusBonusExp          = 5
+)
+
+type TimeTrackingService interface {
+	StartTimer(userID uuid.UUID, taskID uuid.UUID, req models.StartTimerRequest) (*models.TimeEntry, error)
+	StopTimer(userID uuid.UUID) (*models.TimeEntry, error)
+	GetActiveTimer(userID uuid.UUID) (*models.TimeEntry, error)
+	GetTaskTotal(userID uuid.UUID, taskID uuid.UUID) (*models.TaskTimeTotal, error)
+	GetDailyTotals(userID uuid.UUID, from, to time.Time) ([]models.DailyTimeTotal, error)
+}
+
+type timeTrackingService struct {
+	timeEntryRepo repositories.TimeEntryRepository
+	taskRepo      repositories.TaskRepository
+	petRepo       repositories.PetRepository
+	uow           repositories.UnitOfWork
+	pets          petClock
+}
+
+func NewTimeTrackingService(timeEntryRepo repositories.TimeEntryRepository, taskRepo repositories.TaskRepository, petRepo repositories.PetRepository, vacationRepo repositories.VacationRepository, uow repositories.UnitOfWork, petCfg PetConfig) TimeTrackingService {
+	return &timeTrackingService{timeEntryRepo: timeEntryRepo, taskRepo: taskRepo, petRepo: petRepo, uow: uow, pets: petClock{petRepo: petRepo, vacationRepo: vacationRepo, cfg: petCfg}}
+}
+
+func (s *timeTrackingService) StartTimer(userID uuid.UUID, taskID uuid.UUID, req models.StartTimerRequest) (*models.TimeEntry, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	if task.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	if task.Completed {
+		return nil, errors.New("task already completed")
+	}
+
+	// Only one running timer per user
+	if _, err := s.timeEntryRepo.FindActiveByUserID(userID); err == nil {
+		return nil, errors.New("a timer is already running")
+	}
+
+	entry := &models.TimeEntry{
+		UserID:    userID,
+		TaskID:    taskID,
+		Kind:      "timer",
+		StartedAt: time.Now(),
+	}
+
+	if req.Kind == "pomodoro" {
+		entry.Kind = "pomodoro"
+		entry.PlannedMinutes = req.PlannedMinutes
+		if entry.PlannedMinutes == 0 {
+			entry.PlannedMinutes = defaultPomodoroMinutes
+		}
+	}
+
+	// The partial unique index on user_id catches concurrent starts
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		return nil, errors.New("a timer is already running")
+	}
+
+	return entry, nil
+}
+
+func (s *timeTrackingService) StopTimer(userID uuid.UUID) (*models.TimeEntry, error) {
+	var entry *models.TimeEntry
+	err := s.uow.Do(func(repos repositories.Repositories) error {
+		var err error
+		if entry, err = repos.TimeEntries.FindActiveByUserID(userID); err != nil {
+			return errors.New("no timer is running")
+		}
+
+		now := time.Now()
+		entry.EndedAt = &now
+		entry.DurationSeconds = int(now.Sub(entry.StartedAt).Seconds())
+
+		// A pomodoro counts as a focus session only if it ran its full length
+		if entry.Kind == "pomodoro" && entry.DurationSeconds >= entry.PlannedMinutes*60 {
+			entry.FocusCompleted = true
+			entry.BonusExp = focusBonusExp
+		}
+
+		// Only the stop that actually closes the entry pays the bonus
+		closed, err := repos.TimeEntries.Close(entry)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return errors.New("no timer is running")
+		}
+
+		if entry.FocusCompleted {
+			pets := s.pets.in(repos)
+			pet, err := pets.find(userID)
+			if err == nil && pet.Health != PetFainted {
+				pets.cfg.Progression.AddExp(pet, entry.BonusExp)
+				if err := pets.save(pet, now); err != nil {
+					return err
+				}
+				return achievementEngine{repos: repos}.publish(userID, EventPetProgressed)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (s *timeTrackingService) GetActiveTimer(userID uuid.UUID) (*models.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, errors.New("no timer is running")
+	}
+	return entry, nil
+}
+
+func (s *timeTrackingService) GetTaskTotal(userID uuid.UUID, taskID uuid.UUID) (*models.TaskTimeTotal, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	if task.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return s.timeEntryRepo.SumByTask(taskID)
+}
+
+func (s *timeTrackingService) GetDailyTotals(userID uuid.UUID, from, to time.Time) ([]models.DailyTimeTotal, error) {
+	if !to.After(from) {
+		return nil, errors.New("invalid date range")
+	}
+	return s.timeEntryRepo.SumByDay(userID, from, to)
+}
+
+// internal/services/attachment_service.go
+
+const (
+	downloadURLTTL     = 15 * time.Minute
+	thumbnailMaxSide   = 256
+	thumbnailMaxPixels = 40_000_000
+)
+
+// allowedAttachmentTypes lists sniffed content types we accept
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+type AttachmentService interface {
+	Upload(userID uuid.UUID, taskID uuid.UUID, fileName string, r io.Reader) (*models.AttachmentResponse, error)
+	GetAttachments(userID uuid.UUID, taskID uuid.UUID) ([]models.AttachmentResponse, error)
+	DeleteAttachment(userID uuid.UUID, attachmentID uuid.UUID) error
+	PurgeOrphans() (int, error)
+	OpenAttachment(attachmentID uuid.UUID, variant string, expires int64, signature string) (*models.Attachment, io.ReadCloser, error)
+	MaxUploadBytes() int64
+}
+
+type attachmentService struct {
+	attachmentRepo repositories.AttachmentRepository
+	taskRepo       repositories.TaskRepository
+	uow            repositories.UnitOfWork
+	store          storage.BlobStore
+	signer         *storage.URLSigner
+	appURL         string
+	maxBytes       int64
+	quotaBytes     int64
+}
+
+func NewAttachmentService(attachmentRepo repositories.AttachmentRepository, taskRepo repositories.TaskRepository, uow repositories.UnitOfWork, store storage.BlobStore, signer *storage.URLSigner, appURL string, maxBytes, quotaBytes int64) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		uow:            uow,
+		store:          store,
+		signer:         signer,
+		appURL:         appURL,
+		maxBytes:       maxBytes,
+		quotaBytes:     quotaBytes,
+	}
+}
+
+func (s *attachmentService) MaxUploadBytes() int64 {
+	return s.maxBytes
+}
+
+func (s *attachmentService) Upload(userID uuid.UUID, taskID uuid.UUID, fileName string, r io.Reader) (*models.AttachmentResponse, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	if task.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	// Read one byte past the limit to detect oversized files
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, errors.New("file is too large")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	// Fail fast before writing blobs; checked again under the lock below
+	if err := s.checkQuota(s.attachmentRepo, userID, int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	// Trust the bytes, not the client-supplied type or extension
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || !allowedAttachmentTypes[contentType] {
+		return nil, errors.New("unsupported file type")
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New(),
+		UserID:      userID,
+		TaskID:      taskID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	attachment.StorageKey = userID.String() + "/" + attachment.ID.String()
+
+	ctx := context.Background()
+	if err := s.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		// A broken image still uploads; it just has no thumbnail
+		if thumb, err := makeThumbnail(data); err == nil {
+			key := attachment.StorageKey + ".thumb.jpg"
+			if err := s.store.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err == nil {
+				attachment.ThumbnailKey = key
+			}
+		}
+	}
+
+	// The user lock keeps concurrent uploads from overshooting the quota
+	err = s.uow.Do(func(repos repositories.Repositories) error {
+		if err := repos.Users.Lock(userID); err != nil {
+			return err
+		}
+		if err := s.checkQuota(repos.Attachments, userID, attachment.Size); err != nil {
+			return err
+		}
+		return repos.Attachments.Create(attachment)
+	})
+	if err != nil {
+		deleteBlobs(s.store, attachment)
+		return nil, err
+	}
+
+	return s.toResponse(attachment), nil
+}
+
+func (s *attachmentService) checkQuota(attachmentRepo repositories.AttachmentRepository, userID uuid.UUID, size int64) error {
+	used, err := attachmentRepo.SumSizeByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if used+size > s.quotaBytes {
+		return errors.New("attachment quota exceeded")
+	}
+	return nil
+}
+
+func (s *attachmentService) GetAttachments(userID uuid.UUID, taskID uuid.UUID) ([]models.AttachmentResponse, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+
+	if task.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	attachments, err := s.attachmentRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.AttachmentResponse, len(attachments))
+	for i := range attachments {
+		responses[i] = *s.toResponse(&attachments[i])
+	}
+	return responses, nil
+}
+
+func (s *attachmentService) DeleteAttachment(userID uuid.UUID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return errors.New("attachment not found")
+	}
+
+	if attachment.UserID != userID {
+		return errors.New("unauthorized")
+	}
+
+	if err := s.attachmentRepo.Delete(attachmentID); err != nil {
+		return err
+	}
+
+	deleteBlobs(s.store, attachment)
+	return nil
+}
+
+// PurgeOrphans deletes attachments (and their blobs) whose task is gone,
+// returning how many it deleted
+func (s *attachmentService) PurgeOrphans() (int, error) {
+	purged := 0
+	for {
+		orphans, err := s.attachmentRepo.FindOrphaned(100)
+		if err != nil || len(orphans) == 0 {
+			return purged, err
+		}
+		for i := range orphans {
+			if err := s.attachmentRepo.Delete(orphans[i].ID); err != nil {
+				return purged, err
+			}
+			deleteBlobs(s.store, &orphans[i])
+			purged++
+		}
+	}
+}
+
+func (s *attachmentService) OpenAttachment(attachmentID uuid.UUID, variant string, expires int64, signature string) (*models.Attachment, io.ReadCloser, error) {
+	if !s.signer.Verify(attachmentID.String()+":"+variant, expires, signature) {
+		return nil, nil, errors.New("invalid or expired link")
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	key := attachment.StorageKey
+	if variant == "thumbnail" {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, errors.New("attachment not found")
+		}
+		key = attachment.ThumbnailKey
+		attachment.ContentType = "image/jpeg"
+	}
+
+	body, err := s.store.Get(context.Background(), key)
+	if err != nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	return attachment, body, nil
+}
+
+func (s *attachmentService) toResponse(attachment *models.Attachment) *models.AttachmentResponse {
+	resp := &models.AttachmentResponse{
+		Attachment:  *attachment,
+		DownloadURL: s.signedURL(attachment.ID, "original"),
+	}
+	if attachment.ThumbnailKey != "" {
+		resp.ThumbnailURL = s.signedURL(attachment.ID, "thumbnail")
+	}
+	return resp
+}
+
+func (s *attachmentService) signedURL(attachmentID uuid.UUID, variant string) string {
+	expires := time.Now().Add(downloadURLTTL)
+	query := url.Values{
+		"variant":   {variant},
+		"expires":   {strconv.FormatInt(expires.Unix(), 10)},
+		"signature": {s.signer.Sign(attachmentID.String()+":"+variant, expires)},
+	}
+	return s.appURL + "/api/v1/attachments/" + attachmentID.String() + "/download?" + query.Encode()
+}
+
+func deleteBlobs(store storage.BlobStore, attachment *models.Attachment) {
+	ctx := context.Background()
+	if err := store.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Printf("failed to delete blob %s: %v", attachment.StorageKey, err)
+	}
+	if attachment.ThumbnailKey != "" {
+		if err := store.Delete(ctx, attachment.ThumbnailKey); err != nil {
+			log.Printf("failed to delete blob %s: %v", attachment.ThumbnailKey, err)
+		}
+	}
+}
+
+// makeThumbnail scales an image to fit a thumbnailMaxSide box, as JPEG
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > thumbnailMaxPixels {
+		return nil, errors.New("image too large for thumbnail")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w > thumbnailMaxSide || h > thumbnailMaxSide {
+		if w >= h {
+			w, h = thumbnailMaxSide, max(1, h*thumbnailMaxSide/w)
+		} else {
+			w, h = max(1, w*thumbnailMaxSide/h), thumbnailMaxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
 // Utility functions
+
 func clamp(value, min, max int) int {
 	if value < min {
 		return min
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"guildquest/internal/models"
+)
+
+// streakNow is mid-afternoon on a fixed day; the test users are on UTC
+var streakNow = time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+func streakDay(daysAgo int) string {
+	return streakNow.AddDate(0, 0, -daysAgo).Format(dayLayout)
+}
+
+func settleStreak(t *testing.T, db *fakeDB, streak models.Streak) models.Streak {
+	t.Helper()
+	if err := (streakTracker{repos: db.repos()}).settle(&streak, time.UTC, streakNow); err != nil {
+		t.Fatal(err)
+	}
+	return streak
+}
+
+func TestStreakSettleSpendsFreezes(t *testing.T) {
+	db := newFakeDB()
+	alice := db.addUser("alice@example.com", 0)
+
+	// Last extended three days ago: the two days since were missed
+	streak := settleStreak(t, db, models.Streak{UserID: alice.ID, Current: 5, LastDay: streakDay(3), Freezes: 3})
+	if streak.Current != 5 || streak.Freezes != 1 || streak.LastDay != streakDay(1) {
+		t.Fatalf("got streak %d with %d freezes up to %s, want 5 with 1 up to %s", streak.Current, streak.Freezes, streak.LastDay, streakDay(1))
+	}
+
+	// Yesterday and today aren't missed yet
+	for _, last := range []string{streakDay(1), streakDay(0)} {
+		streak := settleStreak(t, db, models.Streak{UserID: alice.ID, Current: 5, LastDay: last, Freezes: 1})
+		if streak.Current != 5 || streak.Freezes != 1 || streak.LastDay != last {
+			t.Fatalf("streak last extended %s was settled to %d with %d freezes up to %s", last, streak.Current, streak.Freezes, streak.LastDay)
+		}
+	}
+}
+
+func TestStreakBreaksWithoutEnoughFreezes(t *testing.T) {
+	db := newFakeDB()
+	alice := db.addUser("alice@example.com", 0)
+
+	streak := settleStreak(t, db, models.Streak{UserID: alice.ID, Current: 5, Longest: 8, LastDay: streakDay(3), Freezes: 1})
+	if streak.Current != 0 || streak.Longest != 8 || streak.Freezes != 1 {
+		t.Fatalf("got streak %d (longest %d) with %d freezes, want 0 (longest 8) keeping its 1 freeze", streak.Current, streak.Longest, streak.Freezes)
+	}
+}
+
+func TestStreakSettleSkipsVacationDays(t *testing.T) {
+	db := newFakeDB()
+	alice := db.addUser("alice@example.com", 0)
+
+	// Away for the three days before yesterday; yesterday was missed
+	start, _ := time.Parse(dayLayout, streakDay(4))
+	db.vacations = append(db.vacations, models.Vacation{UserID: alice.ID, StartsAt: start, EndsAt: start.AddDate(0, 0, 3)})
+
+	streak := settleStreak(t, db, models.Streak{UserID: alice.ID, Current: 5, LastDay: streakDay(5), Freezes: 1})
+	if streak.Current != 5 || streak.Freezes != 0 || streak.LastDay != streakDay(1) {
+		t.Fatalf("got streak %d with %d freezes up to %s, want 5 with 0 up to %s", streak.Current, streak.Freezes, streak.LastDay, streakDay(1))
+	}
+
+	// Without the freeze for yesterday the vacation doesn't save it
+	streak = settleStreak(t, db, models.Streak{UserID: alice.ID, Current: 5, LastDay: streakDay(5)})
+	if streak.Current != 0 {
+		t.Fatalf("got streak %d, want it broken by the day after the vacation", streak.Current)
+	}
+}
+
+func TestStreakRecordPaysMilestones(t *testing.T) {
+	db := newFakeDB()
+	alice := db.addUser("alice@example.com", 0)
+	db.streaks[alice.ID] = models.Streak{UserID: alice.ID, Current: 2, Longest: 2, LastDay: streakDay(1)}
+	tracker := streakTracker{repos: db.repos()}
+
+	// Completing a second quest the same day doesn't extend it again
+	for i := 0; i < 2; i++ {
+		if err := tracker.record(alice.ID, streakNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	streak := db.streaks[alice.ID]
+	if streak.Current != 3 || streak.Longest != 3 || streak.LastDay != streakDay(0) {
+		t.Fatalf("got streak %d (longest %d) up to %s, want 3 up to %s", streak.Current, streak.Longest, streak.LastDay, streakDay(0))
+	}
+	if db.gold[alice.ID] != streakMilestones[0].Gold {
+		t.Fatalf("got %d gold, want the %d-day bonus of %d", db.gold[alice.ID], streakMilestones[0].Days, streakMilestones[0].Gold)
+	}
+}
+
+func TestStreakSeededFromQuestHistory(t *testing.T) {
+	db := newFakeDB()
+	alice := db.addUser("alice@example.com", 0)
+	// Quests on each of the last three days but not today, and one before a gap
+	for _, daysAgo := range []int{1, 2, 3, 5} {
+		db.completions[alice.ID] = append(db.completions[alice.ID], streakNow.AddDate(0, 0, -daysAgo))
+	}
+
+	streak, err := streakTracker{repos: db.repos()}.lock(alice.ID, time.UTC, streakNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streak.Current != 3 || streak.LastDay != streakDay(1) {
+		t.Fatalf("seeded streak %d up to %s, want 3 up to %s", streak.Current, streak.LastDay, streakDay(1))
+	}
+}
+
+func TestCheckInOncePerDay(t *testing.T) {
+	db := newFakeDB()
+	streaks := NewStreakService(db.repos().Work, StreakConfig{CheckInGold: 5, FreezePrice: 50, MaxFreezes: 2})
+	alice := db.addUser("alice@example.com", 0)
+
+	if _, err := streaks.CheckIn(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := streaks.CheckIn(alice.ID); err == nil {
+		t.Fatal("checked in twice in one day")
+	}
+	if db.gold[alice.ID] != 5 {
+		t.Fatalf("got %d gold, want one check-in's 5", db.gold[alice.ID])
+	}
+}
+
+func TestBuyFreezeLimit(t *testing.T) {
+	db := newFakeDB()
+	streaks := NewStreakService(db.repos().Work, StreakConfig{CheckInGold: 5, FreezePrice: 50, MaxFreezes: 2})
+	alice := db.addUser("alice@example.com", 500)
+
+	for i := 0; i < 2; i++ {
+		if _, err := streaks.BuyFreeze(alice.ID, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := streaks.BuyFreeze(alice.ID, ""); err == nil {
+		t.Fatal("bought a freeze past the limit")
+	}
+	if db.streaks[alice.ID].Freezes != 2 || db.gold[alice.ID] != 400 {
+		t.Fatalf("got %d freezes and %d gold, want 2 and 400", db.streaks[alice.ID].Freezes, db.gold[alice.ID])
+	}
+}
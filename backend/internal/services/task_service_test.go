@@ -0,0 +1,102 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"guildquest/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// addColumn stores tasks with the given ranks in a new column
+func addColumn(db *fakeDB, ranks ...float64) (uuid.UUID, []uuid.UUID) {
+	statusID := uuid.New()
+	ids := make([]uuid.UUID, len(ranks))
+	for i, rank := range ranks {
+		ids[i] = uuid.New()
+		db.tasks[ids[i]] = models.Task{ID: ids[i], StatusID: &statusID, Rank: rank}
+	}
+	return statusID, ids
+}
+
+func TestMidRank(t *testing.T) {
+	column := []models.Task{{Rank: 1}, {Rank: 2}, {Rank: 4}}
+	for _, tt := range []struct {
+		slot int
+		want float64
+	}{
+		{0, 0},
+		{1, 1.5},
+		{2, 3},
+		{3, 5},
+	} {
+		if got, ok := midRank(column, tt.slot); !ok || got != tt.want {
+			t.Errorf("midRank(slot %d) = %v, %v; want %v", tt.slot, got, ok, tt.want)
+		}
+	}
+	if got, ok := midRank(nil, 0); !ok || got != 1 {
+		t.Errorf("midRank of an empty column = %v, %v; want 1", got, ok)
+	}
+
+	tight := []models.Task{{Rank: 1}, {Rank: math.Nextafter(1, 2)}}
+	if _, ok := midRank(tight, 1); ok {
+		t.Error("split two ranks with nothing between them")
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	db := newFakeDB()
+	tasks := &taskService{taskRepo: db.repos().Tasks}
+	statusID, ids := addColumn(db, 1, 2, 3)
+	moving := uuid.New()
+
+	for _, tt := range []struct {
+		name          string
+		before, after *uuid.UUID
+		want          float64
+	}{
+		{"bottom", nil, nil, 4},
+		{"top", nil, &ids[0], 0},
+		{"after the first", &ids[0], nil, 1.5},
+		{"between neighbours", &ids[1], &ids[2], 2.5},
+	} {
+		if got, err := tasks.rankBetween(statusID, moving, tt.before, tt.after); err != nil || got != tt.want {
+			t.Errorf("%s: rank %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	// Neighbours that aren't adjacent, or aren't in the column
+	if _, err := tasks.rankBetween(statusID, moving, &ids[0], &ids[2]); err == nil {
+		t.Error("placed a task between tasks that aren't neighbours")
+	}
+	if _, err := tasks.rankBetween(statusID, moving, &moving, nil); err == nil {
+		t.Error("placed a task after a task outside the column")
+	}
+
+	// A task moving within its column ignores its own rank
+	if got, err := tasks.rankBetween(statusID, ids[0], &ids[2], nil); err != nil || got != 4 {
+		t.Errorf("moving the first task last: rank %v, %v; want 4", got, err)
+	}
+}
+
+// When float precision runs out between two neighbours the column is
+// respaced, keeping its order
+func TestRankBetweenRespacesColumn(t *testing.T) {
+	db := newFakeDB()
+	tasks := &taskService{taskRepo: db.repos().Tasks}
+	statusID, ids := addColumn(db, 1, math.Nextafter(1, 2), 5)
+
+	rank, err := tasks.rankBetween(statusID, uuid.New(), &ids[0], &ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range ids {
+		if got := db.tasks[id].Rank; got != float64(i+1) {
+			t.Fatalf("task %d respaced to rank %v, want %d", i, got, i+1)
+		}
+	}
+	if rank != 1.5 {
+		t.Fatalf("rank %v after respacing, want 1.5", rank)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"guildquest/internal/models"
+)
+
+func newVacationTest(perYear int) (*fakeDB, VacationService) {
+	db := newFakeDB()
+	repos := db.repos()
+	return db, NewVacationService(repos.Vacations, repos.Work, 14, perYear)
+}
+
+func vacationDay(days int) string {
+	return time.Now().UTC().AddDate(0, 0, days).Format(dayLayout)
+}
+
+func TestStartVacationChecksDates(t *testing.T) {
+	db, vacations := newVacationTest(3)
+	alice := db.addUser("alice@example.com", 0)
+
+	for _, req := range []models.StartVacationRequest{
+		{StartDate: vacationDay(-1), EndDate: vacationDay(2)},
+		{StartDate: vacationDay(3), EndDate: vacationDay(2)},
+		{EndDate: vacationDay(14)},
+		{EndDate: "next week"},
+	} {
+		if _, err := vacations.StartVacation(alice.ID, req); err == nil {
+			t.Errorf("started a vacation from %q to %q", req.StartDate, req.EndDate)
+		}
+	}
+
+	// Starting today starts now; the last day is included
+	before := time.Now()
+	vacation, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{EndDate: vacationDay(13)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	end, _ := time.Parse(dayLayout, vacationDay(14))
+	if vacation.StartsAt.Before(before) || !vacation.EndsAt.Equal(end) {
+		t.Fatalf("vacation runs %s to %s, want now to %s", vacation.StartsAt, vacation.EndsAt, end)
+	}
+}
+
+func TestStartVacationOneAtATime(t *testing.T) {
+	db, vacations := newVacationTest(3)
+	alice := db.addUser("alice@example.com", 0)
+
+	if _, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{StartDate: vacationDay(5), EndDate: vacationDay(7)}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{EndDate: vacationDay(1)})
+	if err == nil || !strings.Contains(err.Error(), "already") {
+		t.Fatalf("second vacation returned %v, want already scheduled", err)
+	}
+	if len(db.vacations) != 1 {
+		t.Fatalf("%d vacations stored, want 1", len(db.vacations))
+	}
+}
+
+// Cutting a vacation short counts toward the yearly limit; cancelling one
+// that hasn't started doesn't
+func TestVacationYearlyLimit(t *testing.T) {
+	db, vacations := newVacationTest(1)
+	alice := db.addUser("alice@example.com", 0)
+
+	if _, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{StartDate: vacationDay(5), EndDate: vacationDay(7)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vacations.EndVacation(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.vacations) != 0 {
+		t.Fatalf("cancelled vacation still stored")
+	}
+
+	if _, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{EndDate: vacationDay(2)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vacations.EndVacation(alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.vacations) != 1 || db.vacations[0].EndedAt == nil {
+		t.Fatalf("vacation cut short wasn't kept with its end")
+	}
+
+	if _, err := vacations.StartVacation(alice.ID, models.StartVacationRequest{EndDate: vacationDay(2)}); err == nil {
+		t.Fatal("started a second vacation in a year with a limit of one")
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// TestS3StoreRoundTrip needs an S3-compatible server; `docker-compose up -d
+// minio` starts one on localhost:9000
+func TestS3StoreRoundTrip(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+
+	store, err := NewS3Store(S3Options{
+		Endpoint:  endpoint,
+		Region:    "us-east-1",
+		Bucket:    getenv("S3_TEST_BUCKET", "guildquest-test"),
+		AccessKey: getenv("S3_TEST_ACCESS_KEY", "minioadmin"),
+		SecretKey: getenv("S3_TEST_SECRET_KEY", "minioadmin"),
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "test/" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	content := "hello from the round trip"
+
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatalf("reading blob: %v", err)
+	}
+	if string(got) != content {
+		t.Fatalf("Get returned %q, want %q", got, content)
+	}
+
+	// Downloads are served through signed links to the stored key
+	signer := NewURLSigner("test-secret")
+	expires := time.Now().Add(time.Minute)
+	signature := signer.Sign(key, expires)
+	if !signer.Verify(key, expires.Unix(), signature) {
+		t.Fatal("signed link for the blob did not verify")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete returned %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,67 @@
+// internal/storage/storage.go
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"guildquest/internal/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque file contents under string keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the blob store selected by STORAGE_DRIVER
+func New(cfg *config.Config) (BlobStore, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		return NewLocalStore(cfg.StorageLocalPath)
+	case "s3":
+		return NewS3Store(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+// URLSigner produces and checks expiring signatures for download links
+type URLSigner struct {
+	secret []byte
+}
+
+func NewURLSigner(secret string) *URLSigner {
+	return &URLSigner{secret: []byte(secret)}
+}
+
+func (s *URLSigner) Sign(resource string, expires time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(resource + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *URLSigner) Verify(resource string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	expected := s.Sign(resource, time.Unix(expires, 0))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
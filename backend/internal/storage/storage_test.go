@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestURLSigner(t *testing.T) {
+	signer := NewURLSigner("test-secret")
+	expires := time.Now().Add(time.Minute)
+	signature := signer.Sign("attachment:original", expires)
+
+	if !signer.Verify("attachment:original", expires.Unix(), signature) {
+		t.Error("valid signature rejected")
+	}
+	if signer.Verify("attachment:thumbnail", expires.Unix(), signature) {
+		t.Error("signature accepted for another resource")
+	}
+	if signer.Verify("attachment:original", expires.Add(time.Hour).Unix(), signature) {
+		t.Error("signature accepted with a later expiry")
+	}
+	if NewURLSigner("other-secret").Verify("attachment:original", expires.Unix(), signature) {
+		t.Error("signature accepted under another secret")
+	}
+
+	past := time.Now().Add(-time.Minute)
+	if signer.Verify("attachment:original", past.Unix(), signer.Sign("attachment:original", past)) {
+		t.Error("expired signature accepted")
+	}
+}